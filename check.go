@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/report"
+)
+
+type CheckCmd struct {
+	Warn    model.Duration `default:"90d" help:"Warn about releases reaching EOL within this duration (exit code 1)."`
+	Fail    model.Duration `default:"30d" help:"Fail on releases reaching EOL within this duration or already EOL (exit code 2)."`
+	Format  string         `short:"o" enum:"table,json,markdown,sarif,junit" default:"table" help:"Output format. Must be \"table\", \"json\", \"markdown\", \"sarif\" or \"junit\"."`
+	Timeout time.Duration  `default:"2m" help:"Timeout for fetching all products from the endoflife.date API."`
+}
+
+// checkError carries the exit code of the check command.
+type checkError struct {
+	status report.Status
+	count  int
+}
+
+func (e checkError) Error() string {
+	return fmt.Sprintf("check finished with %d release(s) in status %q", e.count, e.status)
+}
+
+func (e checkError) ExitCode() int {
+	return e.status.ExitCode()
+}
+
+func (c *CheckCmd) Validate() error {
+	if c.Fail > c.Warn {
+		return fmt.Errorf("--fail (%s) must not be greater than --warn (%s)", c.Fail, c.Warn)
+	}
+	return nil
+}
+
+func (c *CheckCmd) Run(globals *Globals) error {
+	cfg, err := config.LoadConfig(globals.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	exporter, err := collector.NewExporter(*cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create exporter: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	if err := exporter.Refresh(ctx); err != nil {
+		slog.Warn("Failed to fetch some products", "error", err)
+	}
+
+	rep := report.Evaluate(exporter.Products(), report.Thresholds{
+		Warn: time.Duration(c.Warn),
+		Fail: time.Duration(c.Fail),
+	}, time.Now())
+
+	rep.ConfigFile = globals.Config
+	if rep.ProductLines, err = config.ProductLines(globals.Config); err != nil {
+		slog.Warn("Failed to locate products in configuration", "error", err)
+	}
+
+	if err := rep.Write(os.Stdout, c.Format); err != nil {
+		return err
+	}
+
+	if status := rep.Status(); status != report.StatusOK {
+		return checkError{status: status, count: rep.Count(status)}
+	}
+	return nil
+}
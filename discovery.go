@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/docker"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/host"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/kubernetes"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/manifest"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/prometheus"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/sbom"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/terraform"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+// DiscoveryFlags holds the flags of the discovery sources.
+type DiscoveryFlags struct {
+	Kubernetes kubernetes.Config `embed:"" prefix:"kubernetes." envprefix:"KUBERNETES_"`
+	Docker     docker.Config     `embed:"" prefix:"docker." envprefix:"DOCKER_"`
+	SBOM       sbom.Config       `embed:"" prefix:"sbom." envprefix:"SBOM_"`
+	Manifest   manifest.Config   `embed:"" prefix:"manifest." envprefix:"MANIFEST_"`
+	Host       host.Config       `embed:"" prefix:"host." envprefix:"HOST_"`
+	Prometheus prometheus.Config `embed:"" prefix:"prometheus." envprefix:"PROMETHEUS_"`
+	Terraform  terraform.Config  `embed:"" prefix:"terraform." envprefix:"TERRAFORM_"`
+}
+
+// newDiscoverer returns a discoverer for the enabled sources, nil if no
+// source is enabled.
+func newDiscoverer(flags DiscoveryFlags, cfg *config.Config) (*discovery.Discoverer, error) {
+	ec, err := endoflife.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	resolver := discovery.NewResolver(cfg.Discovery.Mappings, ec)
+
+	var sources []discovery.Source
+	if flags.Kubernetes.Enabled || flags.Kubernetes.Cluster || flags.Kubernetes.Helm {
+		client, err := kubernetes.NewClient(flags.Kubernetes)
+		if err != nil {
+			return nil, err
+		}
+		if flags.Kubernetes.Enabled {
+			sources = append(sources, kubernetes.NewWorkloadSource(client, flags.Kubernetes.Namespaces, resolver))
+		}
+		if flags.Kubernetes.Cluster {
+			sources = append(sources, kubernetes.NewClusterSource(client))
+		}
+		if flags.Kubernetes.Helm {
+			sources = append(sources, kubernetes.NewHelmSource(client, flags.Kubernetes.Namespaces, resolver))
+		}
+	}
+
+	if flags.Docker.Host != "" {
+		source, err := docker.New(flags.Docker, resolver)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	if flags.SBOM.Path != "" {
+		sources = append(sources, sbom.New(flags.SBOM.Path, resolver))
+	}
+
+	if flags.Manifest.Path != "" {
+		sources = append(sources, manifest.New(flags.Manifest.Path, resolver))
+	}
+
+	if flags.Host.Enabled {
+		sources = append(sources, host.New(flags.Host, resolver))
+	}
+
+	if flags.Prometheus.URL != "" {
+		var rules []prometheus.Rule
+		for _, rule := range cfg.Discovery.Prometheus {
+			rules = append(rules, prometheus.Rule{Query: rule.Query, Label: rule.Label, Product: rule.Product})
+		}
+		source, err := prometheus.New(flags.Prometheus, rules)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	if flags.Terraform.Path != "" {
+		sources = append(sources, terraform.New(flags.Terraform.Path, resolver))
+	}
+
+	if len(sources) == 0 {
+		return nil, nil
+	}
+	return discovery.New(sources...), nil
+}
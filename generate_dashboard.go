@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/dashboard"
+)
+
+type GenerateDashboardCmd struct {
+	Title string `default:"End of Life Exporter" help:"Title of the dashboard."`
+	UID   string `name:"uid" default:"endoflife-exporter" help:"UID of the dashboard."`
+}
+
+func (c *GenerateDashboardCmd) Run(globals *Globals) error {
+	cfg, err := config.LoadConfig(globals.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	out, err := dashboard.Generate(dashboard.Options{
+		Title:        c.Title,
+		UID:          c.UID,
+		CustomLabels: cfg.LabelNames(),
+	}).Marshal()
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(out)
+	return err
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/rules"
+)
+
+type GenerateRulesCmd struct {
+	Format    string            `enum:"plain,crd" default:"plain" help:"Output format. Must be \"plain\" (Prometheus rule file) or \"crd\" (PrometheusRule resource)."`
+	Name      string            `default:"endoflife-exporter" help:"Name of the PrometheusRule resource (crd only)."`
+	Namespace string            `help:"Namespace of the PrometheusRule resource (crd only)."`
+	Labels    map[string]string `help:"Labels of the PrometheusRule resource, e.g. release=prometheus (crd only)."`
+}
+
+func (c *GenerateRulesCmd) Run(globals *Globals) error {
+	cfg, err := config.LoadConfig(globals.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	out, err := rules.Generate(*cfg).Marshal(c.Format, rules.ObjectMeta{
+		Name:      c.Name,
+		Namespace: c.Namespace,
+		Labels:    c.Labels,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(out)
+	return err
+}
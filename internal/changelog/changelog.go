@@ -0,0 +1,160 @@
+// Package changelog detects changes between two snapshots of a product's
+// release cycles and keeps a bounded log of them.
+package changelog
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+const dateLayout = "2006-01-02"
+
+// Type is the kind of change of a release cycle.
+type Type string
+
+const (
+	TypeNewReleaseCycle   Type = "new_release_cycle"
+	TypeNewLatestVersion  Type = "new_latest_version"
+	TypeEOLDateChanged    Type = "eol_date_changed"
+	TypeMaintainedChanged Type = "maintained_changed"
+)
+
+// Types lists all change types.
+var Types = []Type{TypeNewReleaseCycle, TypeNewLatestVersion, TypeEOLDateChanged, TypeMaintainedChanged}
+
+// Change is a single change of a release cycle between two refreshes.
+type Change struct {
+	Time         time.Time `json:"time"`
+	Product      string    `json:"product"`
+	ReleaseCycle string    `json:"release_cycle"`
+	Type         Type      `json:"type"`
+	Old          string    `json:"old,omitempty"`
+	New          string    `json:"new"`
+}
+
+// Message describes the change in a single sentence.
+func (c Change) Message() string {
+	switch c.Type {
+	case TypeNewReleaseCycle:
+		return fmt.Sprintf("%s %s released, latest version %s", c.Product, c.ReleaseCycle, c.New)
+	case TypeNewLatestVersion:
+		return fmt.Sprintf("%s %s: new latest version %s (was %s)", c.Product, c.ReleaseCycle, c.New, c.Old)
+	case TypeEOLDateChanged:
+		return fmt.Sprintf("%s %s: end-of-life moved from %s to %s", c.Product, c.ReleaseCycle, c.Old, c.New)
+	case TypeMaintainedChanged:
+		if c.New == "true" {
+			return fmt.Sprintf("%s %s is maintained again", c.Product, c.ReleaseCycle)
+		}
+		return fmt.Sprintf("%s %s is no longer maintained", c.Product, c.ReleaseCycle)
+	}
+	return fmt.Sprintf("%s %s: %s changed from %s to %s", c.Product, c.ReleaseCycle, c.Type, c.Old, c.New)
+}
+
+// Diff returns the changes from the old to the new release cycles of a
+// product. Release cycles that are no longer tracked are not reported.
+func Diff(product string, old, new []endoflife.ReleaseDetails, now time.Time) []Change {
+	var changes []Change
+
+	for _, rel := range new {
+		idx := slices.IndexFunc(old, func(o endoflife.ReleaseDetails) bool {
+			return o.ReleaseCycleName == rel.ReleaseCycleName
+		})
+		change := Change{Time: now, Product: product, ReleaseCycle: rel.ReleaseCycleName}
+
+		if idx < 0 {
+			change.Type = TypeNewReleaseCycle
+			change.New = rel.LatestVersion
+			changes = append(changes, change)
+			continue
+		}
+		prev := old[idx]
+
+		if prev.LatestVersion != rel.LatestVersion {
+			change.Type = TypeNewLatestVersion
+			change.Old, change.New = prev.LatestVersion, rel.LatestVersion
+			changes = append(changes, change)
+		}
+		if !prev.EOLFrom.Equal(rel.EOLFrom) {
+			change.Type = TypeEOLDateChanged
+			change.Old, change.New = formatEOL(prev), formatEOL(rel)
+			changes = append(changes, change)
+		}
+		if prev.IsMaintained != rel.IsMaintained {
+			change.Type = TypeMaintainedChanged
+			change.Old, change.New = fmt.Sprint(prev.IsMaintained), fmt.Sprint(rel.IsMaintained)
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+func formatEOL(rel endoflife.ReleaseDetails) string {
+	if !lifecycle.HasEOLDate(rel) {
+		return "unknown"
+	}
+	return rel.EOLFrom.Format(dateLayout)
+}
+
+// Log keeps the most recent changes and counts all changes per product and
+// type. It is safe for concurrent use.
+type Log struct {
+	size int
+
+	mu      sync.RWMutex
+	changes []Change
+	counts  map[string]map[Type]int
+}
+
+// NewLog returns a log that keeps the last size changes.
+func NewLog(size int) *Log {
+	return &Log{size: size, counts: make(map[string]map[Type]int)}
+}
+
+// Add appends changes to the log, dropping the oldest ones beyond its size.
+func (l *Log) Add(changes ...Change) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, c := range changes {
+		if l.counts[c.Product] == nil {
+			l.counts[c.Product] = make(map[Type]int)
+		}
+		l.counts[c.Product][c.Type]++
+	}
+
+	l.changes = append(l.changes, changes...)
+	if len(l.changes) > l.size {
+		l.changes = slices.Clone(l.changes[len(l.changes)-l.size:])
+	}
+}
+
+// Changes returns the logged changes, newest first.
+func (l *Log) Changes() []Change {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	changes := slices.Clone(l.changes)
+	slices.Reverse(changes)
+	return changes
+}
+
+// Counts returns the number of changes per product and type since start.
+func (l *Log) Counts() map[string]map[Type]int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	counts := make(map[string]map[Type]int, len(l.counts))
+	for product, types := range l.counts {
+		counts[product] = make(map[Type]int, len(types))
+		for t, n := range types {
+			counts[product][t] = n
+		}
+	}
+	return counts
+}
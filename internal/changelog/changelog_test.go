@@ -0,0 +1,75 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+func TestChangelog(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Changelog Suite")
+}
+
+var _ = Describe("Changelog Suite", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	eol := time.Date(2027, 8, 31, 0, 0, 0, 0, time.UTC)
+
+	old := []endoflife.ReleaseDetails{
+		{ReleaseCycleName: "7.0", LatestVersion: "7.0.11", EOLFrom: eol, IsMaintained: true},
+		{ReleaseCycleName: "6.0", LatestVersion: "6.0.20", EOLFrom: endoflife.UnknownDate, IsMaintained: true},
+	}
+
+	Context("When diffing snapshots", func() {
+		It("should report nothing for identical snapshots", func() {
+			Expect(Diff("mongo", old, old, now)).To(BeEmpty())
+		})
+
+		It("should report new cycles, versions, EOL dates and maintenance", func() {
+			updated := []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "8.0", LatestVersion: "8.0.0", EOLFrom: endoflife.UnknownDate, IsMaintained: true},
+				{ReleaseCycleName: "7.0", LatestVersion: "7.0.12", EOLFrom: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), IsMaintained: true},
+				{ReleaseCycleName: "6.0", LatestVersion: "6.0.20", EOLFrom: endoflife.UnknownDate, IsMaintained: false},
+			}
+
+			changes := Diff("mongo", old, updated, now)
+
+			Expect(changes).To(Equal([]Change{
+				{Time: now, Product: "mongo", ReleaseCycle: "8.0", Type: TypeNewReleaseCycle, New: "8.0.0"},
+				{Time: now, Product: "mongo", ReleaseCycle: "7.0", Type: TypeNewLatestVersion, Old: "7.0.11", New: "7.0.12"},
+				{Time: now, Product: "mongo", ReleaseCycle: "7.0", Type: TypeEOLDateChanged, Old: "2027-08-31", New: "2028-02-29"},
+				{Time: now, Product: "mongo", ReleaseCycle: "6.0", Type: TypeMaintainedChanged, Old: "true", New: "false"},
+			}))
+			Expect(changes[2].Message()).To(Equal("mongo 7.0: end-of-life moved from 2027-08-31 to 2028-02-29"))
+			Expect(changes[3].Message()).To(Equal("mongo 6.0 is no longer maintained"))
+		})
+
+		It("should not report release cycles that are no longer tracked", func() {
+			Expect(Diff("mongo", old, old[:1], now)).To(BeEmpty())
+		})
+	})
+
+	Context("When logging changes", func() {
+		It("should keep the newest changes and count all of them", func() {
+			log := NewLog(2)
+			log.Add(
+				Change{Product: "mongo", ReleaseCycle: "7.0", Type: TypeNewLatestVersion, New: "7.0.12"},
+				Change{Product: "mongo", ReleaseCycle: "7.0", Type: TypeNewLatestVersion, New: "7.0.13"},
+			)
+			log.Add(Change{Product: "redis", ReleaseCycle: "8.0", Type: TypeNewReleaseCycle, New: "8.0.0"})
+
+			changes := log.Changes()
+			Expect(changes).To(HaveLen(2))
+			Expect(changes[0].Product).To(Equal("redis"))
+			Expect(changes[1].New).To(Equal("7.0.13"))
+
+			Expect(log.Counts()).To(Equal(map[string]map[Type]int{
+				"mongo": {TypeNewLatestVersion: 2},
+				"redis": {TypeNewReleaseCycle: 1},
+			}))
+		})
+	})
+})
@@ -2,143 +2,676 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/veerendra2/endoflife_exporter/internal/changelog"
 	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	"github.com/veerendra2/endoflife_exporter/internal/history"
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
 	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
 )
 
-var (
-	EndOfLifeProductInfoDesc = prometheus.NewDesc(
-		"endoflife_product_info",
-		"Product release cycle information with EOL status, LTS flag, and maintenance state.",
-		[]string{
-			"is_eol",
-			"is_lts",
-			"is_maintained",
-			"latest_version",
-			"product_name",
-			"release_cycle_name",
-		}, nil,
-	)
-	EndOfLifeLatestVersionTimestampSecondsDesc = prometheus.NewDesc(
-		"endoflife_latest_version_timestamp_seconds",
-		"Release date of the latest version in the release cycle in Unix timestamp.",
-		[]string{
-			"product_name",
-			"release_cycle_name",
-			"latest_version",
-		}, nil,
-	)
-	EndOfLifeReleaseCycleTimestampSecondsDesc = prometheus.NewDesc(
-		"endoflife_release_cycle_timestamp_seconds",
-		"Initial release date of the release cycle in Unix timestamp.",
-		[]string{
-			"product_name",
-			"release_cycle_name",
-		}, nil,
-	)
-	EndOfLifeEolFromTimestampSecondsDesc = prometheus.NewDesc(
-		"endoflife_eol_from_timestamp_seconds",
-		"End-of-life date when the release cycle support ends in Unix timestamp.",
-		[]string{
-			"product_name",
-			"release_cycle_name",
-		}, nil,
-	)
-)
+// ProductStatus holds the cached release cycles of a product together with
+// the outcome of the most recent fetch.
+type ProductStatus struct {
+	Name        string
+	Label       string
+	Link        string
+	Configured  bool // False for products that are only discovered
+	Installed   []string
+	Discovered  []discovery.Installation
+	Labels      map[string]string // Custom labels from the configuration
+	Releases    []endoflife.ReleaseDetails
+	LastAttempt time.Time
+	LastSuccess time.Time
+	Err         error
+
+	// All release cycles of the last successful fetch, not only the tracked
+	upstream []endoflife.ReleaseDetails
+}
 
 type Exporter struct {
 	config    *config.Config
 	eolClient endoflife.Client
+
+	// Custom label names are appended to the labels of every metric
+	customLabels []string
+	descs        map[string]*prometheus.Desc
+
+	// Changes between refreshes, for the feed and endoflife_changes_total
+	changes *changelog.Log
+
+	// Observed changes of lifecycle dates
+	history *history.History
+
+	// Optional discovery of installed versions, its label names are added to
+	// the installed version metric
+	discovery       *discovery.Discoverer
+	discoveryLabels []string
+
+	// Called after every refresh, registered before Run
+	onRefresh []func()
+
+	// Requests a refresh ahead of the interval, pending requests are coalesced
+	refreshNow chan struct{}
+
+	mu       sync.RWMutex
+	products map[string]ProductStatus
 }
 
+// changeLogSize is the number of changes kept for the feed.
+const changeLogSize = 500
+
 func NewExporter(cfg config.Config) (*Exporter, error) {
 	ec, err := endoflife.NewClient()
 	if err != nil {
 		return nil, err
 	}
+
+	customLabels := cfg.LabelNames()
+	descs := make(map[string]*prometheus.Desc, len(Metrics))
+	for _, m := range Metrics {
+		descs[m.Name] = m.Desc(customLabels...)
+	}
+
 	return &Exporter{
-		config:    &cfg,
-		eolClient: ec,
+		config:       &cfg,
+		eolClient:    ec,
+		customLabels: customLabels,
+		descs:        descs,
+		changes:      changelog.NewLog(changeLogSize),
+		history:      history.New(),
+		products:     make(map[string]ProductStatus, len(cfg.Products)),
+		refreshNow:   make(chan struct{}, 1),
 	}, nil
 }
 
 func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
-	ch <- EndOfLifeProductInfoDesc
-	ch <- EndOfLifeLatestVersionTimestampSecondsDesc
-	ch <- EndOfLifeReleaseCycleTimestampSecondsDesc
-	ch <- EndOfLifeEolFromTimestampSecondsDesc
+	for _, m := range Metrics {
+		ch <- e.descs[m.Name]
+	}
 }
 
-func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// labelValues appends the custom label values of the product to values.
+func (e *Exporter) labelValues(status ProductStatus, values ...string) []string {
+	for _, name := range e.customLabels {
+		values = append(values, status.Labels[name])
+	}
+	return values
+}
+
+// Run refreshes the cached product data immediately and then every interval
+// until ctx is cancelled.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		if err := e.Refresh(refreshCtx); err != nil {
+			slog.Warn("Refresh finished with errors", "error", err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-e.refreshNow:
+		}
+	}
+}
+
+// RefreshNow makes Run refresh ahead of its interval, e.g. when the input of
+// a discovery source changed. It does not block, requests made during a
+// refresh result in a single refresh after it.
+func (e *Exporter) RefreshNow() {
+	select {
+	case e.refreshNow <- struct{}{}:
+	default:
+	}
+}
+
+// Refresh discovers the installed versions and fetches the release cycles of
+// all configured and discovered products and updates the cache. Products that
+// fail to fetch keep their previously cached releases. The returned error
+// joins the errors of all failed products and discovery sources.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	var errs []error
+
+	if e.discovery != nil {
+		if err := e.discovery.Refresh(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("discovery: %w", err))
+		}
+	}
+	discovered := e.discoveredVersions()
+
+	// Saved after all products, the history is written to disk
+	var dateChanges []history.Change
+	for _, product := range e.trackedProducts() {
+		details, upstream, err := e.fetchProduct(ctx, product, discovered[product.Name])
+		now := time.Now()
+
+		e.mu.Lock()
+		status := e.products[product.Name]
+		// Only diff against releases of a previous successful fetch
+		if err == nil && !status.LastSuccess.IsZero() {
+			// New cycles are detected upstream, also when only specific
+			// cycles are tracked, all other changes of the tracked cycles
+			changes := slices.DeleteFunc(changelog.Diff(product.Name, status.Releases, details.Releases, now), isNewReleaseCycle)
+			e.changes.Add(append(newReleaseCycles(product.Name, status, upstream, now), changes...)...)
+			dateChanges = append(dateChanges, history.Diff(product.Name, status.Releases, details.Releases, now)...)
+		}
+		status.Name = product.Name
+		status.LastAttempt = now
+		status.Err = err
+		if err == nil {
+			status.LastSuccess = now
+			status.upstream = upstream
+		}
+		// Partial results are only used when nothing is cached yet
+		if err == nil || len(status.Releases) == 0 {
+			status.Label = details.Label
+			status.Link = details.Link
+			status.Releases = details.Releases
+		}
+		e.products[product.Name] = status
+		e.mu.Unlock()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", product.Name, err))
+		}
+	}
+
+	if err := e.history.Add(dateChanges...); err != nil {
+		slog.Warn("Failed to save date history", "error", err)
+	}
+
+	for _, fn := range e.onRefresh {
+		fn()
+	}
+
+	return errors.Join(errs...)
+}
+
+// SetDiscovery adds the installations found by d to the installed versions.
+// The label names of its sources and a source label are added to the
+// installed version metric, they must not clash with the custom labels. It
+// must be called before Run.
+func (e *Exporter) SetDiscovery(d *discovery.Discoverer) error {
+	labels := append([]string{"source"}, d.LabelNames()...)
+	for _, name := range labels {
+		if slices.Contains(e.customLabels, name) || slices.Contains(InstalledVersionInfoMetric.Labels, name) {
+			return fmt.Errorf("discovery label %q clashes with a custom label", name)
+		}
+	}
+
+	e.discovery = d
+	e.discoveryLabels = labels
+	e.descs[InstalledVersionInfoMetric.Name] = InstalledVersionInfoMetric.Desc(append(slices.Clone(labels), e.customLabels...)...)
+	return nil
+}
+
+// discoveredVersions returns the discovered versions by product.
+func (e *Exporter) discoveredVersions() map[string][]string {
+	versions := make(map[string][]string)
+	if e.discovery == nil {
+		return versions
+	}
+	for _, installation := range e.discovery.Installations() {
+		versions[installation.Product] = append(versions[installation.Product], installation.Version)
+	}
+	return versions
+}
+
+// trackedProducts returns the configured products followed by the discovered
+// products that are not configured, sorted by name. Only the release cycles
+// of the discovered versions are tracked for the latter.
+func (e *Exporter) trackedProducts() []config.Product {
+	products := slices.Clone(e.config.Products)
+
+	var names []string
+	for name := range e.discoveredVersions() {
+		if !slices.ContainsFunc(e.config.Products, func(p config.Product) bool { return p.Name == name }) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		products = append(products, config.Product{Name: name})
+	}
+	return products
+}
+
+// SetHistory replaces the in-memory date history, e.g. with a persisted one.
+// It must be called before Run.
+func (e *Exporter) SetHistory(h *history.History) {
+	e.history = h
+}
+
+// DateChanges returns the observed changes of lifecycle dates, newest first.
+func (e *Exporter) DateChanges() []history.Change {
+	return e.history.Changes()
+}
+
+// OnRefresh registers fn to be called after every refresh. It must be called
+// before Run.
+func (e *Exporter) OnRefresh(fn func()) {
+	e.onRefresh = append(e.onRefresh, fn)
+}
+
+// Snapshot is the persisted form of the cached product data.
+type Snapshot struct {
+	Products map[string]ProductSnapshot `json:"products"`
+}
+
+// ProductSnapshot holds the releases of the last successful fetch of a product.
+type ProductSnapshot struct {
+	Label       string                     `json:"label,omitempty"`
+	Link        string                     `json:"link,omitempty"`
+	Releases    []endoflife.ReleaseDetails `json:"releases"`
+	LastAttempt time.Time                  `json:"last_attempt"`
+	LastSuccess time.Time                  `json:"last_success"`
+}
+
+// Snapshot returns the cached product data of all successfully fetched
+// products.
+func (e *Exporter) Snapshot() Snapshot {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	snapshot := Snapshot{Products: make(map[string]ProductSnapshot, len(e.products))}
+	for name, status := range e.products {
+		if status.LastSuccess.IsZero() {
+			continue
+		}
+		snapshot.Products[name] = ProductSnapshot{
+			Label:       status.Label,
+			Link:        status.Link,
+			Releases:    status.Releases,
+			LastAttempt: status.LastAttempt,
+			LastSuccess: status.LastSuccess,
+		}
+	}
+	return snapshot
+}
+
+// Restore fills the cache from a snapshot, e.g. at startup before the first
+// refresh. Products that are no longer configured are ignored.
+func (e *Exporter) Restore(snapshot Snapshot) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
 	for _, product := range e.config.Products {
-		var releases []endoflife.ReleaseDetails
-		var err error
-
-		if product.AllReleases {
-			// Fetch all release cycles for the product
-			releases, err = e.eolClient.GetProductDetails(ctx, product.Name)
-			if err != nil {
-				slog.Error("Failed to get all release cycles", "product_name", product.Name, "error", err)
-				continue
+		ps, ok := snapshot.Products[product.Name]
+		if !ok {
+			continue
+		}
+		e.products[product.Name] = ProductStatus{
+			Name:        product.Name,
+			Label:       ps.Label,
+			Link:        ps.Link,
+			Releases:    ps.Releases,
+			LastAttempt: ps.LastAttempt,
+			LastSuccess: ps.LastSuccess,
+		}
+	}
+}
+
+// Products returns the cached status of all configured products in config
+// order, followed by the discovered products. Products that were never
+// fetched have a zero LastAttempt.
+func (e *Exporter) Products() []ProductStatus {
+	var installations []discovery.Installation
+	if e.discovery != nil {
+		installations = e.discovery.Installations()
+	}
+	products := e.trackedProducts()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	statuses := make([]ProductStatus, 0, len(products))
+	for _, product := range products {
+		status, ok := e.products[product.Name]
+		if !ok {
+			status = ProductStatus{Name: product.Name}
+		}
+		status.Configured = slices.ContainsFunc(e.config.Products, func(p config.Product) bool { return p.Name == product.Name })
+		status.Installed = product.Installed
+		status.Labels = product.Labels
+		status.Discovered = nil
+		for _, installation := range installations {
+			if installation.Product == product.Name {
+				status.Discovered = append(status.Discovered, installation)
 			}
-		} else {
-			// Fetch specific releases
-			for _, releaseName := range product.Releases {
-				relInfo, err := e.eolClient.GetRelease(ctx, product.Name, releaseName)
-				if err != nil {
-					slog.Error("Failed to get release cycle", "product_name", product.Name, "release_name", releaseName, "error", err)
-					continue
-				}
-				releases = append(releases, relInfo)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Changes returns the changes detected between refreshes, newest first.
+func (e *Exporter) Changes() []changelog.Change {
+	return e.changes.Changes()
+}
+
+// newCycleMaxAge is how long after its release a release cycle is reported as
+// new when the previous upstream cycles are unknown.
+const newCycleMaxAge = 30 * 24 * time.Hour
+
+func isNewReleaseCycle(change changelog.Change) bool {
+	return change.Type == changelog.TypeNewReleaseCycle
+}
+
+// newReleaseCycles returns the release cycles added upstream since the previous
+// successful fetch, whether they are tracked or not. Cycles that only start
+// being tracked, e.g. by a discovered version of an old cycle, are not new.
+// Without the previous upstream cycles, e.g. after restoring a snapshot, the
+// tracked cycles are compared instead and only recently released cycles are
+// reported.
+func newReleaseCycles(product string, status ProductStatus, upstream []endoflife.ReleaseDetails, now time.Time) []changelog.Change {
+	previous, restored := status.upstream, status.upstream == nil
+	if restored {
+		previous = status.Releases
+	}
+
+	return slices.DeleteFunc(changelog.Diff(product, previous, upstream, now), func(change changelog.Change) bool {
+		if !isNewReleaseCycle(change) {
+			return true
+		}
+		rel, _ := findRelease(upstream, change.ReleaseCycle)
+		return restored && !rel.ReleaseCycleDate.IsZero() && now.Sub(rel.ReleaseCycleDate) > newCycleMaxAge
+	})
+}
+
+// fetchProduct fetches the configured release cycles of a single product and
+// the cycles of its discovered versions. The product details already contain
+// all release cycles, so a single request is made per product and the
+// releases are picked from it. "latest" refers to the most recently released
+// cycle. All release cycles of the product are returned as upstream.
+func (e *Exporter) fetchProduct(ctx context.Context, product config.Product, discovered []string) (details endoflife.Product, upstream []endoflife.ReleaseDetails, err error) {
+	details, err = e.eolClient.GetProductDetails(ctx, product.Name)
+	if err != nil {
+		slog.Error("Failed to get product details", "product_name", product.Name, "error", err)
+		return details, nil, err
+	}
+	upstream = details.Releases
+
+	if product.AllReleases {
+		return details, upstream, nil
+	}
+
+	// Pick specific releases
+	var releases []endoflife.ReleaseDetails
+	var errs []error
+	for _, releaseName := range product.Releases {
+		relInfo, ok := findRelease(details.Releases, releaseName)
+		if !ok {
+			slog.Error("Failed to get release cycle", "product_name", product.Name, "release_name", releaseName)
+			errs = append(errs, fmt.Errorf("release cycle %q not found", releaseName))
+			continue
+		}
+		releases = append(releases, relInfo)
+	}
+
+	// Discovered versions of unknown cycles are skipped when collecting
+	for _, version := range discovered {
+		relInfo, ok := lifecycle.MatchRelease(version, details.Releases)
+		if ok && !slices.ContainsFunc(releases, func(rel endoflife.ReleaseDetails) bool { return rel.ReleaseCycleName == relInfo.ReleaseCycleName }) {
+			releases = append(releases, relInfo)
+		}
+	}
+	details.Releases = releases
+
+	return details, upstream, errors.Join(errs...)
+}
+
+// findRelease returns the release cycle with the given name, "latest" returns
+// the cycle with the most recent release date.
+func findRelease(releases []endoflife.ReleaseDetails, name string) (endoflife.ReleaseDetails, bool) {
+	var found endoflife.ReleaseDetails
+	ok := false
+	for _, rel := range releases {
+		switch {
+		case name == "latest":
+			if !ok || rel.ReleaseCycleDate.After(found.ReleaseCycleDate) {
+				found, ok = rel, true
+			}
+		case rel.ReleaseCycleName == name:
+			return rel, true
+		}
+	}
+	return found, ok
+}
+
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	changeCounts := e.changes.Counts()
+	dateHistory := e.history.Cycles()
+
+	if e.discovery != nil {
+		e.collectDiscovery(ch)
+	}
+
+	for _, status := range e.Products() {
+		if !status.LastAttempt.IsZero() {
+			fetchSuccess := 0.0
+			if status.Err == nil {
+				fetchSuccess = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				e.descs[ProductFetchSuccessMetric.Name],
+				prometheus.GaugeValue,
+				fetchSuccess,
+				e.labelValues(status,
+					status.Name,
+				)...,
+			)
+		}
+
+		if !status.LastSuccess.IsZero() {
+			ch <- prometheus.MustNewConstMetric(
+				e.descs[ProductLastSuccessTimestampSecondsMetric.Name],
+				prometheus.GaugeValue,
+				float64(status.LastSuccess.Unix()),
+				e.labelValues(status,
+					status.Name,
+				)...,
+			)
+		}
+
+		if !status.LastSuccess.IsZero() {
+			counts := changeCounts[status.Name]
+			for _, changeType := range changelog.Types {
+				ch <- prometheus.MustNewConstMetric(
+					e.descs[ChangesTotalMetric.Name],
+					prometheus.CounterValue,
+					float64(counts[changeType]),
+					e.labelValues(status,
+						string(changeType),
+						status.Name,
+					)...,
+				)
 			}
 		}
 
-		// Process and export metrics for all releases
-		for _, relInfo := range releases {
+		// Process and export metrics for all cached releases
+		for _, relInfo := range status.Releases {
 			ch <- prometheus.MustNewConstMetric(
-				EndOfLifeProductInfoDesc,
+				e.descs[ProductInfoMetric.Name],
 				prometheus.GaugeValue,
 				1,
-				strconv.FormatBool(relInfo.IsEol),
-				strconv.FormatBool(relInfo.IsLts),
-				strconv.FormatBool(relInfo.IsMaintained),
-				relInfo.LatestVersion,
-				product.Name,
-				relInfo.ReleaseCycleName,
+				e.labelValues(status,
+					strconv.FormatBool(relInfo.IsEol),
+					strconv.FormatBool(relInfo.IsLts),
+					strconv.FormatBool(relInfo.IsMaintained),
+					relInfo.LatestVersion,
+					status.Name,
+					relInfo.ReleaseCycleName,
+				)...,
 			)
 
 			ch <- prometheus.MustNewConstMetric(
-				EndOfLifeLatestVersionTimestampSecondsDesc,
+				e.descs[LatestVersionTimestampSecondsMetric.Name],
 				prometheus.GaugeValue,
 				float64(relInfo.LatestVersionDate.Unix()),
-				product.Name,
-				relInfo.ReleaseCycleName,
-				relInfo.LatestVersion,
+				e.labelValues(status,
+					status.Name,
+					relInfo.ReleaseCycleName,
+					relInfo.LatestVersion,
+				)...,
 			)
 
 			ch <- prometheus.MustNewConstMetric(
-				EndOfLifeReleaseCycleTimestampSecondsDesc,
+				e.descs[ReleaseCycleTimestampSecondsMetric.Name],
 				prometheus.GaugeValue,
 				float64(relInfo.ReleaseCycleDate.Unix()),
-				product.Name,
-				relInfo.ReleaseCycleName,
+				e.labelValues(status,
+					status.Name,
+					relInfo.ReleaseCycleName,
+				)...,
 			)
 
 			ch <- prometheus.MustNewConstMetric(
-				EndOfLifeEolFromTimestampSecondsDesc,
+				e.descs[EolFromTimestampSecondsMetric.Name],
 				prometheus.GaugeValue,
 				float64(relInfo.EOLFrom.Unix()),
-				product.Name,
-				relInfo.ReleaseCycleName,
+				e.labelValues(status,
+					status.Name,
+					relInfo.ReleaseCycleName,
+				)...,
 			)
+
+			e.collectDateHistory(ch, status, relInfo, dateHistory[status.Name][relInfo.ReleaseCycleName])
+
+			// Only products with an active support phase have an EOAS date
+			if !relInfo.EOASFrom.IsZero() {
+				ch <- prometheus.MustNewConstMetric(
+					e.descs[EoasFromTimestampSecondsMetric.Name],
+					prometheus.GaugeValue,
+					float64(relInfo.EOASFrom.Unix()),
+					e.labelValues(status,
+						status.Name,
+						relInfo.ReleaseCycleName,
+					)...,
+				)
+			}
+		}
+
+		// Installed versions are only exported when they match a cached release cycle
+		for _, version := range status.Installed {
+			e.collectInstalled(ch, status, discovery.Installation{Source: "config", Version: version})
+		}
+		for _, installation := range status.Discovered {
+			e.collectInstalled(ch, status, installation)
+		}
+	}
+}
+
+// collectInstalled exports an installed version if it matches a cached
+// release cycle. The source and its labels are only exported with discovery.
+func (e *Exporter) collectInstalled(ch chan<- prometheus.Metric, status ProductStatus, installation discovery.Installation) {
+	relInfo, ok := lifecycle.MatchRelease(installation.Version, status.Releases)
+	if !ok {
+		return
+	}
+
+	values := []string{
+		installation.Version,
+		strconv.FormatBool(relInfo.IsEol),
+		strconv.FormatBool(lifecycle.GetInstalledStatus(installation.Version, relInfo) == lifecycle.StatusOutdated),
+		relInfo.LatestVersion,
+		status.Name,
+		relInfo.ReleaseCycleName,
+	}
+	for _, name := range e.discoveryLabels {
+		if name == "source" {
+			values = append(values, installation.Source)
+		} else {
+			values = append(values, installation.Labels[name])
 		}
 	}
+
+	ch <- prometheus.MustNewConstMetric(
+		e.descs[InstalledVersionInfoMetric.Name],
+		prometheus.GaugeValue,
+		1,
+		e.labelValues(status, values...)...,
+	)
+}
+
+// collectDiscovery exports the outcome of the discovery sources. Custom labels
+// do not apply to them and are left empty.
+func (e *Exporter) collectDiscovery(ch chan<- prometheus.Metric) {
+	for _, source := range e.discovery.Sources() {
+		if source.LastAttempt.IsZero() {
+			continue
+		}
+
+		success := 0.0
+		if source.Err == nil {
+			success = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			e.descs[DiscoverySuccessMetric.Name],
+			prometheus.GaugeValue,
+			success,
+			e.labelValues(ProductStatus{}, source.Name)...,
+		)
+
+		if !source.LastSuccess.IsZero() {
+			ch <- prometheus.MustNewConstMetric(
+				e.descs[DiscoveredInstallationsMetric.Name],
+				prometheus.GaugeValue,
+				float64(len(source.Installations)),
+				e.labelValues(ProductStatus{}, source.Name)...,
+			)
+		}
+	}
+}
+
+// collectDateHistory exports the date changes of a release cycle. Changes of
+// the EOAS and EOES dates are only exported if the cycle has such a date or
+// it changed before.
+func (e *Exporter) collectDateHistory(ch chan<- prometheus.Metric, status ProductStatus, relInfo endoflife.ReleaseDetails, cycles map[history.DateType]history.Cycle) {
+	for _, dateType := range history.DateTypes {
+		cycle, changed := cycles[dateType]
+		if !changed && (dateType == history.DateEOAS && relInfo.EOASFrom.IsZero() ||
+			dateType == history.DateEOES && relInfo.EOESFrom.IsZero()) {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			e.descs[EolDateChangesTotalMetric.Name],
+			prometheus.CounterValue,
+			float64(cycle.Count),
+			e.labelValues(status,
+				string(dateType),
+				status.Name,
+				relInfo.ReleaseCycleName,
+			)...,
+		)
+	}
+
+	if cycle, ok := cycles[history.DateEOL]; ok {
+		ch <- prometheus.MustNewConstMetric(
+			e.descs[EolDatePreviousTimestampSecondsMetric.Name],
+			prometheus.GaugeValue,
+			float64(cycle.Previous.Unix()),
+			e.labelValues(status,
+				status.Name,
+				relInfo.ReleaseCycleName,
+			)...,
+		)
+	}
 }
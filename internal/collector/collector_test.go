@@ -0,0 +1,223 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/veerendra2/endoflife_exporter/internal/changelog"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	"github.com/veerendra2/endoflife_exporter/internal/history"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+func TestCollector(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Collector Suite")
+}
+
+// staticSource is a discovery source returning fixed installations.
+type staticSource []discovery.Installation
+
+func (s staticSource) Name() string { return "static" }
+
+func (s staticSource) LabelNames() []string { return []string{"workload"} }
+
+func (s staticSource) Discover(context.Context) ([]discovery.Installation, error) { return s, nil }
+
+// fakeClient returns fixed product details, the embedded client is nil.
+type fakeClient struct {
+	endoflife.Client
+	products map[string]endoflife.Product
+}
+
+func (c *fakeClient) GetProductDetails(_ context.Context, name string) (endoflife.Product, error) {
+	return c.products[name], nil
+}
+
+var _ = Describe("Collector Suite", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := config.Config{Products: []config.Product{{Name: "mongo", Installed: []string{"7.0.1"}}, {Name: "redis"}}}
+
+	Context("When persisting snapshots", func() {
+		It("should restore the cached product data", func() {
+			exporter, err := NewExporter(cfg)
+			Expect(err).To(BeNil())
+			exporter.products["mongo"] = ProductStatus{
+				Name:        "mongo",
+				Link:        "https://endoflife.date/mongodb",
+				Releases:    []endoflife.ReleaseDetails{{ReleaseCycleName: "7.0", EOLFrom: now, LatestVersion: "7.0.12"}},
+				LastAttempt: now,
+				LastSuccess: now,
+			}
+			// Never fetched successfully, not persisted
+			exporter.products["redis"] = ProductStatus{Name: "redis", LastAttempt: now}
+
+			data, err := json.Marshal(exporter.Snapshot())
+			Expect(err).To(BeNil())
+
+			snapshot := Snapshot{}
+			Expect(json.Unmarshal(data, &snapshot)).To(Succeed())
+			Expect(snapshot.Products).To(HaveLen(1))
+
+			restored, err := NewExporter(cfg)
+			Expect(err).To(BeNil())
+			restored.Restore(snapshot)
+
+			statuses := restored.Products()
+			Expect(statuses[0].Link).To(Equal("https://endoflife.date/mongodb"))
+			Expect(statuses[0].Releases).To(HaveLen(1))
+			Expect(statuses[0].Releases[0].EOLFrom.Equal(now)).To(BeTrue())
+			Expect(statuses[0].LastSuccess.Equal(now)).To(BeTrue())
+			Expect(statuses[0].Installed).To(Equal([]string{"7.0.1"}))
+			Expect(statuses[1].LastAttempt.IsZero()).To(BeTrue())
+		})
+	})
+
+	Context("When exporting the date history", func() {
+		It("should export date changes and the previous EOL date", func() {
+			exporter, err := NewExporter(cfg)
+			Expect(err).To(BeNil())
+			exporter.products["mongo"] = ProductStatus{
+				Name:        "mongo",
+				Releases:    []endoflife.ReleaseDetails{{ReleaseCycleName: "7.0", EOLFrom: now, LatestVersion: "7.0.12"}},
+				LastAttempt: now,
+				LastSuccess: now,
+			}
+			Expect(exporter.history.Add(history.Change{
+				Product: "mongo", ReleaseCycle: "7.0", Date: history.DateEOL, Old: now.AddDate(-1, 0, 0), New: now,
+			})).To(Succeed())
+
+			registry := prometheus.NewPedanticRegistry()
+			Expect(registry.Register(exporter)).To(Succeed())
+
+			Expect(testutil.GatherAndCount(registry, EolDateChangesTotalMetric.Name)).To(Equal(1))
+			Expect(testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP endoflife_eol_date_changes_total Number of observed changes of a lifecycle date of the release cycle, by date type (eol, eoas or eoes).
+# TYPE endoflife_eol_date_changes_total counter
+endoflife_eol_date_changes_total{date_type="eol",product_name="mongo",release_cycle_name="7.0"} 1
+# HELP endoflife_eol_date_previous_timestamp_seconds EOL date of the release cycle before its most recent change in Unix timestamp.
+# TYPE endoflife_eol_date_previous_timestamp_seconds gauge
+endoflife_eol_date_previous_timestamp_seconds{product_name="mongo",release_cycle_name="7.0"} 1.7356896e+09
+`), EolDateChangesTotalMetric.Name, EolDatePreviousTimestampSecondsMetric.Name)).To(Succeed())
+		})
+	})
+
+	Context("When discovering installed versions", func() {
+		It("should track discovered products and export their installed versions", func() {
+			exporter, err := NewExporter(cfg)
+			Expect(err).To(BeNil())
+
+			discoverer := discovery.New(staticSource{
+				{Product: "mongo", Version: "7.0.2", Labels: map[string]string{"workload": "deployment/api"}},
+				{Product: "postgresql", Version: "15.4", Labels: map[string]string{"workload": "statefulset/db"}},
+			})
+			Expect(exporter.SetDiscovery(discoverer)).To(Succeed())
+			Expect(discoverer.Refresh(context.Background())).To(Succeed())
+
+			Expect(exporter.trackedProducts()).To(HaveLen(3))
+			Expect(exporter.trackedProducts()[2].Name).To(Equal("postgresql"))
+			statuses := exporter.Products()
+			Expect(statuses[0].Configured).To(BeTrue())
+			Expect(statuses[2].Configured).To(BeFalse())
+
+			exporter.products["mongo"] = ProductStatus{
+				Name:        "mongo",
+				Releases:    []endoflife.ReleaseDetails{{ReleaseCycleName: "7.0", EOLFrom: now, LatestVersion: "7.0.12"}},
+				LastAttempt: now,
+				LastSuccess: now,
+			}
+			exporter.products["postgresql"] = ProductStatus{
+				Name:        "postgresql",
+				Releases:    []endoflife.ReleaseDetails{{ReleaseCycleName: "15", EOLFrom: now, LatestVersion: "15.4", IsEol: true}},
+				LastAttempt: now,
+				LastSuccess: now,
+			}
+
+			registry := prometheus.NewPedanticRegistry()
+			Expect(registry.Register(exporter)).To(Succeed())
+
+			Expect(testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP endoflife_installed_version_info Installed product version with its release cycle and whether a newer patch version is available.
+# TYPE endoflife_installed_version_info gauge
+endoflife_installed_version_info{installed_version="15.4",is_eol="true",is_outdated="false",latest_version="15.4",product_name="postgresql",release_cycle_name="15",source="static",workload="statefulset/db"} 1
+endoflife_installed_version_info{installed_version="7.0.1",is_eol="false",is_outdated="true",latest_version="7.0.12",product_name="mongo",release_cycle_name="7.0",source="config",workload=""} 1
+endoflife_installed_version_info{installed_version="7.0.2",is_eol="false",is_outdated="true",latest_version="7.0.12",product_name="mongo",release_cycle_name="7.0",source="static",workload="deployment/api"} 1
+# HELP endoflife_discovered_installations Number of installed versions found by the discovery source, including unknown release cycles.
+# TYPE endoflife_discovered_installations gauge
+endoflife_discovered_installations{source="static"} 2
+`), InstalledVersionInfoMetric.Name, DiscoveredInstallationsMetric.Name)).To(Succeed())
+		})
+
+		It("should not report old release cycles added to tracking as new", func() {
+			exporter, err := NewExporter(config.Config{Products: []config.Product{{Name: "postgresql", Releases: []string{"latest"}}}})
+			Expect(err).To(BeNil())
+			today := time.Now().Truncate(24 * time.Hour)
+			client := &fakeClient{products: map[string]endoflife.Product{"postgresql": {Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "17", ReleaseCycleDate: today.AddDate(0, -3, 0), LatestVersion: "17.2"},
+				{ReleaseCycleName: "12", ReleaseCycleDate: time.Date(2019, 10, 3, 0, 0, 0, 0, time.UTC), LatestVersion: "12.22", IsEol: true},
+			}}}}
+			exporter.eolClient = client
+			Expect(exporter.Refresh(context.Background())).To(Succeed())
+
+			Expect(exporter.SetDiscovery(discovery.New(staticSource{
+				{Product: "postgresql", Version: "12.17", Labels: map[string]string{"workload": "pod/legacy"}},
+			}))).To(Succeed())
+			Expect(exporter.Refresh(context.Background())).To(Succeed())
+
+			Expect(exporter.Products()[0].Releases).To(HaveLen(2))
+			Expect(exporter.Changes()).To(BeEmpty())
+
+			// A release cycle released since the last refresh is new
+			postgresql := client.products["postgresql"]
+			postgresql.Releases = append([]endoflife.ReleaseDetails{{ReleaseCycleName: "18", ReleaseCycleDate: today.AddDate(0, 0, -2), LatestVersion: "18.0"}}, postgresql.Releases...)
+			client.products["postgresql"] = postgresql
+			Expect(exporter.Refresh(context.Background())).To(Succeed())
+
+			changes := exporter.Changes()
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Type).To(Equal(changelog.TypeNewReleaseCycle))
+			Expect(changes[0].ReleaseCycle).To(Equal("18"))
+		})
+
+		It("should report new upstream release cycles of products with specific releases", func() {
+			exporter, err := NewExporter(config.Config{Products: []config.Product{{Name: "postgresql", Releases: []string{"15", "16"}}}})
+			Expect(err).To(BeNil())
+			client := &fakeClient{products: map[string]endoflife.Product{"postgresql": {Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "16", LatestVersion: "16.4"},
+				{ReleaseCycleName: "15", LatestVersion: "15.8"},
+			}}}}
+			exporter.eolClient = client
+			Expect(exporter.Refresh(context.Background())).To(Succeed())
+
+			client.products["postgresql"] = endoflife.Product{Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "17", LatestVersion: "17.0"},
+				{ReleaseCycleName: "16", LatestVersion: "16.5"},
+				{ReleaseCycleName: "15", LatestVersion: "15.8"},
+			}}
+			Expect(exporter.Refresh(context.Background())).To(Succeed())
+
+			Expect(exporter.Products()[0].Releases).To(HaveLen(2))
+			changes := exporter.Changes()
+			Expect(changes).To(HaveLen(2))
+			types := map[string]changelog.Type{}
+			for _, change := range changes {
+				types[change.ReleaseCycle] = change.Type
+			}
+			Expect(types).To(Equal(map[string]changelog.Type{"17": changelog.TypeNewReleaseCycle, "16": changelog.TypeNewLatestVersion}))
+		})
+
+		It("should reject discovery labels that clash with custom labels", func() {
+			exporter, err := NewExporter(config.Config{Products: []config.Product{{Name: "mongo", Labels: map[string]string{"workload": "api"}}}})
+			Expect(err).To(BeNil())
+			Expect(exporter.SetDiscovery(discovery.New(staticSource{}))).NotTo(Succeed())
+		})
+	})
+})
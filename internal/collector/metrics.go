@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metric describes a metric exported by the collector. The definitions are
+// shared with the generated alerting rules and dashboards.
+type Metric struct {
+	Name   string
+	Help   string
+	Labels []string
+}
+
+// Desc returns the Prometheus descriptor of the metric with the custom label
+// names appended to its labels.
+func (m Metric) Desc(customLabels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(m.Name, m.Help, append(slices.Clone(m.Labels), customLabels...), nil)
+}
+
+var (
+	ProductInfoMetric = Metric{
+		Name: "endoflife_product_info",
+		Help: "Product release cycle information with EOL status, LTS flag, and maintenance state.",
+		Labels: []string{
+			"is_eol",
+			"is_lts",
+			"is_maintained",
+			"latest_version",
+			"product_name",
+			"release_cycle_name",
+		},
+	}
+	LatestVersionTimestampSecondsMetric = Metric{
+		Name: "endoflife_latest_version_timestamp_seconds",
+		Help: "Release date of the latest version in the release cycle in Unix timestamp.",
+		Labels: []string{
+			"product_name",
+			"release_cycle_name",
+			"latest_version",
+		},
+	}
+	ReleaseCycleTimestampSecondsMetric = Metric{
+		Name: "endoflife_release_cycle_timestamp_seconds",
+		Help: "Initial release date of the release cycle in Unix timestamp.",
+		Labels: []string{
+			"product_name",
+			"release_cycle_name",
+		},
+	}
+	EolFromTimestampSecondsMetric = Metric{
+		Name: "endoflife_eol_from_timestamp_seconds",
+		Help: "End-of-life date when the release cycle support ends in Unix timestamp.",
+		Labels: []string{
+			"product_name",
+			"release_cycle_name",
+		},
+	}
+	EoasFromTimestampSecondsMetric = Metric{
+		Name: "endoflife_eoas_from_timestamp_seconds",
+		Help: "End of active support date of the release cycle in Unix timestamp, only for products with an active support phase.",
+		Labels: []string{
+			"product_name",
+			"release_cycle_name",
+		},
+	}
+	InstalledVersionInfoMetric = Metric{
+		Name: "endoflife_installed_version_info",
+		Help: "Installed product version with its release cycle and whether a newer patch version is available.",
+		Labels: []string{
+			"installed_version",
+			"is_eol",
+			"is_outdated",
+			"latest_version",
+			"product_name",
+			"release_cycle_name",
+		},
+	}
+	ProductFetchSuccessMetric = Metric{
+		Name: "endoflife_product_fetch_success",
+		Help: "Whether the last fetch of the product from the endoflife.date API succeeded.",
+		Labels: []string{
+			"product_name",
+		},
+	}
+	ProductLastSuccessTimestampSecondsMetric = Metric{
+		Name: "endoflife_product_last_success_timestamp_seconds",
+		Help: "Time of the last successful fetch of the product in Unix timestamp.",
+		Labels: []string{
+			"product_name",
+		},
+	}
+	EolDateChangesTotalMetric = Metric{
+		Name: "endoflife_eol_date_changes_total",
+		Help: "Number of observed changes of a lifecycle date of the release cycle, by date type (eol, eoas or eoes).",
+		Labels: []string{
+			"date_type",
+			"product_name",
+			"release_cycle_name",
+		},
+	}
+	EolDatePreviousTimestampSecondsMetric = Metric{
+		Name: "endoflife_eol_date_previous_timestamp_seconds",
+		Help: "EOL date of the release cycle before its most recent change in Unix timestamp.",
+		Labels: []string{
+			"product_name",
+			"release_cycle_name",
+		},
+	}
+	ChangesTotalMetric = Metric{
+		Name: "endoflife_changes_total",
+		Help: "Number of changes to the tracked release cycles detected between refreshes, by change type.",
+		Labels: []string{
+			"change_type",
+			"product_name",
+		},
+	}
+	DiscoverySuccessMetric = Metric{
+		Name: "endoflife_discovery_success",
+		Help: "Whether the last run of the discovery source succeeded.",
+		Labels: []string{
+			"source",
+		},
+	}
+	DiscoveredInstallationsMetric = Metric{
+		Name: "endoflife_discovered_installations",
+		Help: "Number of installed versions found by the discovery source, including unknown release cycles.",
+		Labels: []string{
+			"source",
+		},
+	}
+)
+
+// Metrics lists all metrics exported by the collector.
+var Metrics = []Metric{
+	ProductInfoMetric,
+	LatestVersionTimestampSecondsMetric,
+	ReleaseCycleTimestampSecondsMetric,
+	EolFromTimestampSecondsMetric,
+	EoasFromTimestampSecondsMetric,
+	InstalledVersionInfoMetric,
+	ProductFetchSuccessMetric,
+	ProductLastSuccessTimestampSecondsMetric,
+	ChangesTotalMetric,
+	EolDateChangesTotalMetric,
+	EolDatePreviousTimestampSecondsMetric,
+	DiscoverySuccessMetric,
+	DiscoveredInstallationsMetric,
+}
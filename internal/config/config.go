@@ -4,18 +4,159 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 )
 
+// Alerting holds the thresholds used to generate alerting rules. Unset fields
+// of a product fall back to the global values.
+type Alerting struct {
+	Warn         model.Duration `yaml:"warn,omitempty"`
+	Critical     model.Duration `yaml:"critical,omitempty"`
+	EOAS         *bool          `yaml:"eoas,omitempty"`
+	Outdated     *bool          `yaml:"outdated,omitempty"`
+	FetchFailure *bool          `yaml:"fetch_failure,omitempty"`
+}
+
 type Product struct {
-	Name        string   `yaml:"name"`
-	AllReleases bool     `yaml:"all_releases,omitempty"`
-	Releases    []string `yaml:"releases"`
+	Name        string            `yaml:"name"`
+	AllReleases bool              `yaml:"all_releases,omitempty"`
+	Releases    []string          `yaml:"releases"`
+	Installed   []string          `yaml:"installed,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Alerting    *Alerting         `yaml:"alerting,omitempty"`
+}
+
+// Notifications configures the webhooks notified on lifecycle events.
+type Notifications struct {
+	// StateFile persists the sent notifications across restarts
+	StateFile string    `yaml:"state_file,omitempty"`
+	Webhooks  []Webhook `yaml:"webhooks,omitempty"`
+}
+
+// Webhook is a URL that lifecycle events are posted to. URL and header values
+// may reference environment variables, e.g. ${SLACK_WEBHOOK_URL}.
+type Webhook struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Template is "generic", "slack" or a Go template rendering the JSON payload
+	Template   string            `yaml:"template,omitempty"`
+	Headers    map[string]string `yaml:"headers,omitempty"`
+	Events     []string          `yaml:"events,omitempty"`
+	Thresholds []model.Duration  `yaml:"thresholds,omitempty"`
+	// Products limits the events to the given products, all if empty
+	Products   []string       `yaml:"products,omitempty"`
+	MaxRetries *int           `yaml:"max_retries,omitempty"`
+	Timeout    model.Duration `yaml:"timeout,omitempty"`
+}
+
+// Discovery configures how discovered installations are mapped to products.
+type Discovery struct {
+	// Mappings map image and package names to products, on top of the built-in
+	// mappings, e.g. "registry.example.com/db": postgresql
+	Mappings map[string]string `yaml:"mappings,omitempty"`
+	// Prometheus rules map the series of a query to a product, on top of the
+	// built-in rules for common exporters
+	Prometheus []PrometheusRule `yaml:"prometheus,omitempty"`
+}
+
+// PrometheusRule maps the series of a query to a product, the version is read
+// from the value of a label, e.g. {query: app_build_info, label: version,
+// product: nodejs}.
+type PrometheusRule struct {
+	Query   string `yaml:"query"`
+	Label   string `yaml:"label"`
+	Product string `yaml:"product"`
 }
 
 type Config struct {
-	Products []Product `yaml:"products"`
+	Alerting      Alerting      `yaml:"alerting"`
+	Products      []Product     `yaml:"products"`
+	Notifications Notifications `yaml:"notifications,omitempty"`
+	Discovery     Discovery     `yaml:"discovery,omitempty"`
+}
+
+// reservedLabels are the label names used by the exporter's metrics, custom
+// labels must not override them.
+var reservedLabels = []string{
+	"change_type",
+	"date_type",
+	"installed_version",
+	"is_eol",
+	"is_lts",
+	"is_maintained",
+	"is_outdated",
+	"latest_version",
+	"product_name",
+	"release_cycle_name",
+}
+
+// dashboardVariables are the fixed template variables of the generated
+// dashboard. Custom labels become variables of the same name, so they must
+// not be named like them.
+var dashboardVariables = []string{"datasource", "product"}
+
+// LabelNames returns the sorted union of the custom label names of all products.
+func (c Config) LabelNames() []string {
+	names := []string{}
+	for _, product := range c.Products {
+		for name := range product.Labels {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Merge returns a copy of a with unset fields taken from defaults.
+func (a Alerting) Merge(defaults Alerting) Alerting {
+	if a.Warn == 0 {
+		a.Warn = defaults.Warn
+	}
+	if a.Critical == 0 {
+		a.Critical = defaults.Critical
+	}
+	if a.EOAS == nil {
+		a.EOAS = defaults.EOAS
+	}
+	if a.Outdated == nil {
+		a.Outdated = defaults.Outdated
+	}
+	if a.FetchFailure == nil {
+		a.FetchFailure = defaults.FetchFailure
+	}
+	return a
+}
+
+// validate checks merged thresholds, an alert between critical and warn could
+// never fire if critical was greater.
+func (a Alerting) validate() error {
+	if a.Warn < 0 || a.Critical < 0 {
+		return fmt.Errorf("warn (%s) and critical (%s) must not be negative", a.Warn, a.Critical)
+	}
+	if a.Critical > a.Warn {
+		return fmt.Errorf("critical (%s) must not be greater than warn (%s)", a.Critical, a.Warn)
+	}
+	return nil
+}
+
+// defaultAlerting are the global alerting thresholds if not configured.
+func defaultAlerting() Alerting {
+	enabled := true
+	return Alerting{
+		Warn:         model.Duration(90 * 24 * time.Hour),
+		Critical:     model.Duration(30 * 24 * time.Hour),
+		EOAS:         &enabled,
+		Outdated:     &enabled,
+		FetchFailure: &enabled,
+	}
 }
 
 func LoadConfig(filename string) (*Config, error) {
@@ -34,7 +175,26 @@ func LoadConfig(filename string) (*Config, error) {
 		return nil, fmt.Errorf("no products defined in the configuration")
 	}
 
+	config.Alerting = config.Alerting.Merge(defaultAlerting())
+	if err := config.Alerting.validate(); err != nil {
+		return nil, fmt.Errorf("alerting: %w", err)
+	}
+
 	for i, product := range config.Products {
+		if product.Alerting != nil {
+			if err := product.Alerting.Merge(config.Alerting).validate(); err != nil {
+				return nil, fmt.Errorf("product %s: alerting: %w", product.Name, err)
+			}
+		}
+		for name := range product.Labels {
+			if !model.LabelName(name).IsValidLegacy() || strings.HasPrefix(name, "__") {
+				return nil, fmt.Errorf("product %s: invalid label name %q", product.Name, name)
+			}
+			if slices.Contains(reservedLabels, name) || slices.Contains(dashboardVariables, name) {
+				return nil, fmt.Errorf("product %s: label name %q is reserved", product.Name, name)
+			}
+		}
+
 		// Warn if both all_releases and releases are specified
 		if product.AllReleases && len(product.Releases) > 0 {
 			slog.Warn("Ignoring 'releases' field when 'all_releases' is true", "product", product.Name)
@@ -46,5 +206,94 @@ func LoadConfig(filename string) (*Config, error) {
 		}
 	}
 
+	if err := config.Notifications.setDefaults(config.Alerting); err != nil {
+		return nil, fmt.Errorf("notifications: %w", err)
+	}
+
+	for name, product := range config.Discovery.Mappings {
+		if name == "" || product == "" {
+			return nil, fmt.Errorf("discovery: invalid mapping %q: %q", name, product)
+		}
+	}
+	for i, rule := range config.Discovery.Prometheus {
+		if rule.Query == "" || rule.Label == "" || rule.Product == "" {
+			return nil, fmt.Errorf("discovery: prometheus rule %d: query, label and product are required", i)
+		}
+	}
+
 	return config, nil
 }
+
+// setDefaults validates the webhooks and fills unset fields. Thresholds
+// default to the global alerting warn and critical durations.
+func (n *Notifications) setDefaults(alerting Alerting) error {
+	if len(n.Webhooks) > 0 && n.StateFile == "" {
+		n.StateFile = "notifications.json"
+	}
+
+	names := make(map[string]bool, len(n.Webhooks))
+	for i := range n.Webhooks {
+		webhook := &n.Webhooks[i]
+		if webhook.Name == "" {
+			return fmt.Errorf("webhook %d: name is required", i)
+		}
+		if names[webhook.Name] {
+			return fmt.Errorf("webhook %s: duplicate name", webhook.Name)
+		}
+		names[webhook.Name] = true
+
+		if webhook.URL == "" {
+			return fmt.Errorf("webhook %s: url is required", webhook.Name)
+		}
+		if webhook.Template == "" {
+			webhook.Template = "generic"
+		}
+		if len(webhook.Thresholds) == 0 {
+			webhook.Thresholds = []model.Duration{alerting.Warn, alerting.Critical}
+		}
+		if webhook.MaxRetries == nil {
+			retries := 3
+			webhook.MaxRetries = &retries
+		}
+		if webhook.Timeout == 0 {
+			webhook.Timeout = model.Duration(10 * time.Second)
+		}
+	}
+
+	return nil
+}
+
+// ProductLines returns the line number of each product entry in the
+// configuration file, e.g. to point CI findings at the product definition.
+func ProductLines(filename string) (map[string]int, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	lines := make(map[string]int)
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return lines, nil
+	}
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "products" || root.Content[i+1].Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, product := range root.Content[i+1].Content {
+			for j := 0; j+1 < len(product.Content); j += 2 {
+				if product.Content[j].Value == "name" {
+					lines[product.Content[j+1].Value] = product.Content[j].Line
+				}
+			}
+		}
+	}
+
+	return lines, nil
+}
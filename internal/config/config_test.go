@@ -154,4 +154,205 @@ products:
 			Expect(cfg.Products[0].Releases).To(HaveLen(2))
 		})
 	})
+
+	Context("When loading alerting thresholds", func() {
+		It("should apply defaults and keep product overrides", func() {
+			configContent := `---
+alerting:
+  warn: 120d
+  eoas: false
+products:
+  - name: mongo
+    alerting:
+      critical: 60d
+  - name: redis`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "alerting.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			cfg, err := LoadConfig(filepath)
+
+			Expect(err).To(BeNil())
+			Expect(cfg.Alerting.Warn.String()).To(Equal("120d"))
+			Expect(cfg.Alerting.Critical.String()).To(Equal("30d"))
+			Expect(*cfg.Alerting.EOAS).To(BeFalse())
+			Expect(*cfg.Alerting.Outdated).To(BeTrue())
+
+			Expect(cfg.Products[0].Alerting).NotTo(BeNil())
+			mongo := cfg.Products[0].Alerting.Merge(cfg.Alerting)
+			Expect(mongo.Warn.String()).To(Equal("120d"))
+			Expect(mongo.Critical.String()).To(Equal("60d"))
+			Expect(cfg.Products[1].Alerting).To(BeNil())
+		})
+
+		It("should fail when critical is greater than warn", func() {
+			configContent := `---
+alerting:
+  warn: 10d
+  critical: 30d
+products:
+  - name: mongo`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "invalid_alerting.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			cfg, err := LoadConfig(filepath)
+			Expect(err).NotTo(BeNil())
+			Expect(cfg).To(BeNil())
+		})
+
+		It("should fail when critical of a product is greater than the global warn", func() {
+			configContent := `---
+products:
+  - name: mongo
+    alerting:
+      critical: 120d`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "invalid_product_alerting.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			cfg, err := LoadConfig(filepath)
+			Expect(err).To(MatchError(ContainSubstring("product mongo")))
+			Expect(cfg).To(BeNil())
+		})
+	})
+
+	Context("When loading custom labels", func() {
+		It("should return the sorted label names of all products", func() {
+			configContent := `---
+products:
+  - name: mongo
+    labels:
+      team: data
+      env: prod
+  - name: redis
+    labels:
+      team: cache`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "labels.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			cfg, err := LoadConfig(filepath)
+
+			Expect(err).To(BeNil())
+			Expect(cfg.LabelNames()).To(Equal([]string{"env", "team"}))
+		})
+
+		It("should fail on reserved or invalid label names", func() {
+			for _, name := range []string{"product_name", "product", "datasource", "__team", "team-name"} {
+				configContent := "products:\n  - name: mongo\n    labels:\n      " + name + ": data\n"
+
+				filepath := filepath.Join(GinkgoT().TempDir(), "invalid_labels.yaml")
+				Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+				cfg, err := LoadConfig(filepath)
+				Expect(err).NotTo(BeNil(), name)
+				Expect(cfg).To(BeNil())
+			}
+		})
+	})
+
+	Context("When loading notifications", func() {
+		It("should default thresholds to the alerting thresholds", func() {
+			configContent := `---
+alerting:
+  warn: 60d
+products:
+  - name: mongo
+notifications:
+  webhooks:
+    - name: slack
+      url: https://hooks.slack.com/services/xxx
+      template: slack`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "notifications.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			cfg, err := LoadConfig(filepath)
+
+			Expect(err).To(BeNil())
+			Expect(cfg.Notifications.StateFile).To(Equal("notifications.json"))
+			webhook := cfg.Notifications.Webhooks[0]
+			Expect(webhook.Thresholds).To(HaveLen(2))
+			Expect(webhook.Thresholds[0].String()).To(Equal("60d"))
+			Expect(webhook.Thresholds[1].String()).To(Equal("30d"))
+			Expect(*webhook.MaxRetries).To(Equal(3))
+		})
+
+		It("should fail on webhooks without url", func() {
+			configContent := `---
+products:
+  - name: mongo
+notifications:
+  webhooks:
+    - name: slack`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "invalid_notifications.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			cfg, err := LoadConfig(filepath)
+			Expect(err).NotTo(BeNil())
+			Expect(cfg).To(BeNil())
+		})
+	})
+
+	Context("When loading discovery", func() {
+		It("should load prometheus rules", func() {
+			configContent := `---
+products:
+  - name: nodejs
+discovery:
+  prometheus:
+    - query: app_build_info{job="web"}
+      label: node_version
+      product: nodejs`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "discovery.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			cfg, err := LoadConfig(filepath)
+			Expect(err).To(BeNil())
+			Expect(cfg.Discovery.Prometheus).To(Equal([]PrometheusRule{
+				{Query: `app_build_info{job="web"}`, Label: "node_version", Product: "nodejs"},
+			}))
+		})
+
+		It("should fail on prometheus rules without product", func() {
+			configContent := `---
+products:
+  - name: nodejs
+discovery:
+  prometheus:
+    - query: app_build_info
+      label: node_version`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "invalid_discovery.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			cfg, err := LoadConfig(filepath)
+			Expect(err).NotTo(BeNil())
+			Expect(cfg).To(BeNil())
+		})
+	})
+
+	Context("When locating products", func() {
+		It("should return the line of each product name", func() {
+			configContent := `---
+products:
+  - name: mongo
+    releases:
+      - "8.0"
+  - releases:
+      - "22.04"
+    name: ubuntu`
+
+			filepath := filepath.Join(GinkgoT().TempDir(), "product_lines.yaml")
+			Expect(os.WriteFile(filepath, []byte(configContent), 0644)).To(Succeed())
+
+			lines, err := ProductLines(filepath)
+
+			Expect(err).To(BeNil())
+			Expect(lines).To(Equal(map[string]int{"mongo": 3, "ubuntu": 8}))
+		})
+	})
 })
@@ -0,0 +1,372 @@
+// Package dashboard generates a Grafana dashboard for the metrics of the
+// collector, so the dashboard always matches the running exporter.
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+)
+
+// labelTitles are the column titles of the labels exported by the collector.
+var labelTitles = map[string]string{
+	"installed_version":  "Installed Version",
+	"is_eol":             "End-of-Life",
+	"is_lts":             "LTS",
+	"is_maintained":      "Maintained",
+	"is_outdated":        "Outdated",
+	"latest_version":     "Latest Version",
+	"product_name":       "Product Name",
+	"release_cycle_name": "Release Cycle Name",
+}
+
+// Options configure the generated dashboard.
+type Options struct {
+	Title string
+	UID   string
+	// CustomLabels are the custom label names of the configured products, each
+	// becomes a template variable.
+	CustomLabels []string
+}
+
+type Dashboard struct {
+	Title         string     `json:"title"`
+	UID           string     `json:"uid"`
+	Description   string     `json:"description"`
+	Editable      bool       `json:"editable"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	Time          TimeRange  `json:"time"`
+	Templating    Templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Templating struct {
+	List []Variable `json:"list"`
+}
+
+type Variable struct {
+	Name       string `json:"name"`
+	Label      string `json:"label"`
+	Type       string `json:"type"`
+	Query      any    `json:"query"`
+	Datasource any    `json:"datasource,omitempty"`
+	Definition string `json:"definition,omitempty"`
+	Refresh    int    `json:"refresh,omitempty"`
+	Sort       int    `json:"sort,omitempty"`
+	Multi      bool   `json:"multi"`
+	IncludeAll bool   `json:"includeAll"`
+	AllValue   string `json:"allValue,omitempty"`
+}
+
+type Panel struct {
+	ID              int              `json:"id"`
+	Type            string           `json:"type"`
+	Title           string           `json:"title"`
+	Description     string           `json:"description,omitempty"`
+	Datasource      Datasource       `json:"datasource"`
+	GridPos         GridPos          `json:"gridPos"`
+	Targets         []Target         `json:"targets"`
+	Transformations []Transformation `json:"transformations,omitempty"`
+	FieldConfig     FieldConfig      `json:"fieldConfig"`
+	Options         map[string]any   `json:"options,omitempty"`
+}
+
+type Datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type GridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type Target struct {
+	RefID        string     `json:"refId"`
+	Datasource   Datasource `json:"datasource"`
+	Expr         string     `json:"expr"`
+	LegendFormat string     `json:"legendFormat,omitempty"`
+	Format       string     `json:"format,omitempty"`
+	Instant      bool       `json:"instant"`
+	Range        bool       `json:"range"`
+}
+
+type Transformation struct {
+	ID      string         `json:"id"`
+	Options map[string]any `json:"options"`
+}
+
+type FieldConfig struct {
+	Defaults  map[string]any `json:"defaults"`
+	Overrides []any          `json:"overrides"`
+}
+
+var datasource = Datasource{Type: "prometheus", UID: "${datasource}"}
+
+// Generate builds the dashboard from the metric definitions of the collector.
+func Generate(opts Options) Dashboard {
+	d := Dashboard{
+		Title:         opts.Title,
+		UID:           opts.UID,
+		Description:   "https://endoflife.date/",
+		Editable:      true,
+		SchemaVersion: 41,
+		Tags:          []string{"endoflife"},
+		Timezone:      "browser",
+		Time:          TimeRange{From: "now-5y", To: "now+5y"},
+		Templating:    Templating{List: variables(opts.CustomLabels)},
+	}
+
+	filter := selector(opts.CustomLabels)
+	panels := []Panel{
+		tablePanel("Release Cycles", collector.ProductInfoMetric, opts.CustomLabels,
+			fmt.Sprintf("%s{%s}", collector.ProductInfoMetric.Name, filter), nil),
+		daysToEOLPanel(opts.CustomLabels, filter),
+		timelinePanel(filter),
+		tablePanel("Installed Versions", collector.InstalledVersionInfoMetric, opts.CustomLabels,
+			fmt.Sprintf("%s{%s}", collector.InstalledVersionInfoMetric.Name, filter), nil),
+		fetchStatusPanel(filter),
+	}
+
+	// Two panels per row
+	for i := range panels {
+		panels[i].ID = i + 1
+		panels[i].GridPos = GridPos{H: 10, W: 12, X: (i % 2) * 12, Y: (i / 2) * 10}
+	}
+	d.Panels = panels
+
+	return d
+}
+
+func variables(customLabels []string) []Variable {
+	vars := []Variable{
+		{
+			Name:  "datasource",
+			Label: "Data Source",
+			Type:  "datasource",
+			Query: "prometheus",
+		},
+		labelVariable("product", "product_name", "Product Name"),
+	}
+	for _, name := range customLabels {
+		vars = append(vars, labelVariable(name, name, labelTitle(name)))
+	}
+	return vars
+}
+
+func labelVariable(name, label, title string) Variable {
+	query := fmt.Sprintf("label_values(%s, %s)", collector.ProductInfoMetric.Name, label)
+	return Variable{
+		Name:       name,
+		Label:      title,
+		Type:       "query",
+		Datasource: datasource,
+		Definition: query,
+		Query: map[string]any{
+			"qryType": 1,
+			"query":   query,
+			"refId":   "PrometheusVariableQueryEditor-VariableQuery",
+		},
+		Refresh:    1,
+		Sort:       1,
+		Multi:      true,
+		IncludeAll: true,
+		AllValue:   ".*",
+	}
+}
+
+// selector returns the label matchers for the template variables.
+func selector(customLabels []string) string {
+	matchers := []string{`product_name=~"$product"`}
+	for _, name := range customLabels {
+		matchers = append(matchers, fmt.Sprintf(`%s=~"$%s"`, name, name))
+	}
+	return strings.Join(matchers, ", ")
+}
+
+func tablePanel(title string, m collector.Metric, customLabels []string, expr string, extraColumns map[string]string) Panel {
+	labels := append(append([]string{}, m.Labels...), customLabels...)
+
+	exclude := map[string]bool{"Time": true, "Value": true, "__name__": true, "instance": true, "job": true}
+	index := map[string]int{}
+	rename := map[string]string{}
+	for i, label := range orderLabels(labels) {
+		index[label] = i
+		rename[label] = labelTitle(label)
+	}
+	for column, title := range extraColumns {
+		delete(exclude, column)
+		index[column] = len(index)
+		rename[column] = title
+	}
+
+	return Panel{
+		Type:        "table",
+		Title:       title,
+		Description: m.Help,
+		Datasource:  datasource,
+		Targets: []Target{{
+			RefID:      "A",
+			Datasource: datasource,
+			Expr:       expr,
+			Format:     "table",
+			Instant:    true,
+		}},
+		Transformations: []Transformation{{
+			ID: "organize",
+			Options: map[string]any{
+				"excludeByName": exclude,
+				"indexByName":   index,
+				"renameByName":  rename,
+			},
+		}},
+		FieldConfig: FieldConfig{Defaults: map[string]any{}, Overrides: []any{}},
+		Options:     map[string]any{"showHeader": true},
+	}
+}
+
+func daysToEOLPanel(customLabels []string, filter string) Panel {
+	m := collector.EolFromTimestampSecondsMetric
+	p := tablePanel("Days to End-of-Life", m, customLabels,
+		fmt.Sprintf("sort((%s{%s} - time()) / 86400)", m.Name, filter),
+		map[string]string{"Value": "Days to EOL"})
+
+	p.FieldConfig.Defaults = map[string]any{
+		"decimals": 0,
+		"thresholds": map[string]any{
+			"mode": "absolute",
+			"steps": []map[string]any{
+				{"color": "red", "value": nil},
+				{"color": "orange", "value": 30},
+				{"color": "green", "value": 90},
+			},
+		},
+	}
+	p.FieldConfig.Overrides = []any{
+		map[string]any{
+			"matcher": map[string]any{"id": "byName", "options": "Days to EOL"},
+			"properties": []map[string]any{
+				{"id": "custom.cellOptions", "value": map[string]any{"type": "color-background"}},
+			},
+		},
+	}
+	return p
+}
+
+func timelinePanel(filter string) Panel {
+	return Panel{
+		Type:        "timeseries",
+		Title:       "Versions Timeline",
+		Description: collector.ReleaseCycleTimestampSecondsMetric.Help + " " + collector.EolFromTimestampSecondsMetric.Help,
+		Datasource:  datasource,
+		Targets: []Target{
+			{
+				RefID:        "A",
+				Datasource:   datasource,
+				Expr:         fmt.Sprintf("max(%s{%s} * 1000) by (product_name, release_cycle_name)", collector.EolFromTimestampSecondsMetric.Name, filter),
+				LegendFormat: "{{product_name}} {{release_cycle_name}} EOL",
+				Range:        true,
+			},
+			{
+				RefID:        "B",
+				Datasource:   datasource,
+				Expr:         fmt.Sprintf("max(%s{%s} * 1000) by (product_name, release_cycle_name)", collector.ReleaseCycleTimestampSecondsMetric.Name, filter),
+				LegendFormat: "{{product_name}} {{release_cycle_name}} Release",
+				Range:        true,
+			},
+			{
+				RefID:        "C",
+				Datasource:   datasource,
+				Expr:         "time() * 1000",
+				LegendFormat: "Now",
+				Range:        true,
+			},
+		},
+		FieldConfig: FieldConfig{
+			Defaults:  map[string]any{"unit": "dateTimeAsIso"},
+			Overrides: []any{},
+		},
+	}
+}
+
+func fetchStatusPanel(filter string) Panel {
+	m := collector.ProductFetchSuccessMetric
+	return Panel{
+		Type:        "stat",
+		Title:       "Fetch Status",
+		Description: m.Help,
+		Datasource:  datasource,
+		Targets: []Target{{
+			RefID:        "A",
+			Datasource:   datasource,
+			Expr:         fmt.Sprintf("%s{%s}", m.Name, filter),
+			LegendFormat: "{{product_name}}",
+			Instant:      true,
+		}},
+		FieldConfig: FieldConfig{
+			Defaults: map[string]any{
+				"mappings": []map[string]any{{
+					"type": "value",
+					"options": map[string]any{
+						"0": map[string]any{"text": "Failed", "color": "red"},
+						"1": map[string]any{"text": "OK", "color": "green"},
+					},
+				}},
+			},
+			Overrides: []any{},
+		},
+		Options: map[string]any{"colorMode": "background", "graphMode": "none"},
+	}
+}
+
+// orderLabels puts product_name and release_cycle_name first, the rest keeps
+// its order.
+func orderLabels(labels []string) []string {
+	ordered := []string{}
+	for _, first := range []string{"product_name", "release_cycle_name"} {
+		for _, l := range labels {
+			if l == first {
+				ordered = append(ordered, l)
+			}
+		}
+	}
+	for _, l := range labels {
+		if l != "product_name" && l != "release_cycle_name" {
+			ordered = append(ordered, l)
+		}
+	}
+	return ordered
+}
+
+func labelTitle(label string) string {
+	if title, ok := labelTitles[label]; ok {
+		return title
+	}
+	words := strings.Split(label, "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Marshal renders the dashboard as indented JSON, ready for import into Grafana.
+func (d Dashboard) Marshal() ([]byte, error) {
+	out, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
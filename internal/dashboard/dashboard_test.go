@@ -0,0 +1,86 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+)
+
+func TestDashboard(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dashboard Suite")
+}
+
+var _ = Describe("Dashboard Suite", func() {
+	opts := Options{Title: "EOL", UID: "eol", CustomLabels: []string{"env", "team"}}
+
+	It("should add a template variable per custom label", func() {
+		d := Generate(opts)
+
+		names := []string{}
+		for _, v := range d.Templating.List {
+			names = append(names, v.Name)
+		}
+		Expect(names).To(Equal([]string{"datasource", "product", "env", "team"}))
+		Expect(d.Templating.List[3].Definition).To(Equal("label_values(endoflife_product_info, team)"))
+	})
+
+	It("should filter every query by the template variables", func() {
+		d := Generate(opts)
+
+		Expect(d.Panels).NotTo(BeEmpty())
+		for _, p := range d.Panels {
+			for _, t := range p.Targets {
+				if t.RefID == "C" {
+					continue
+				}
+				Expect(t.Expr).To(ContainSubstring(`product_name=~"$product", env=~"$env", team=~"$team"`), p.Title)
+			}
+		}
+	})
+
+	It("should include custom labels as table columns", func() {
+		d := Generate(opts)
+
+		organize := d.Panels[0].Transformations[0].Options
+		rename := organize["renameByName"].(map[string]string)
+		Expect(rename).To(HaveKeyWithValue("team", "Team"))
+		for _, label := range collector.ProductInfoMetric.Labels {
+			Expect(rename).To(HaveKey(label))
+		}
+	})
+
+	It("should only reference exported metrics", func() {
+		d := Generate(Options{Title: "EOL", UID: "eol"})
+
+		names := []string{}
+		for _, m := range collector.Metrics {
+			names = append(names, m.Name)
+		}
+		for _, p := range d.Panels {
+			for _, t := range p.Targets {
+				if t.RefID == "C" {
+					continue
+				}
+				Expect(t.Expr).To(SatisfyAny(
+					ContainSubstring(names[0]), ContainSubstring(names[1]), ContainSubstring(names[2]),
+					ContainSubstring(names[3]), ContainSubstring(names[4]), ContainSubstring(names[5]),
+					ContainSubstring(names[6]), ContainSubstring(names[7]),
+				))
+			}
+		}
+	})
+
+	It("should marshal to valid JSON", func() {
+		out, err := Generate(opts).Marshal()
+		Expect(err).To(BeNil())
+
+		var decoded map[string]any
+		Expect(json.Unmarshal(out, &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("uid", "eol"))
+		Expect(decoded).To(HaveKeyWithValue("title", "EOL"))
+	})
+})
@@ -0,0 +1,151 @@
+// Package discovery finds the installed versions of products in the
+// environment, e.g. the images running in a Kubernetes cluster, so they do not
+// have to be listed in the configuration.
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Installation is an installed version of a product found by a source.
+type Installation struct {
+	Source  string
+	Product string
+	Version string
+	// Labels identify where the version is installed, their names are the
+	// LabelNames of the source
+	Labels map[string]string
+}
+
+// Source discovers installations, e.g. from the Kubernetes API.
+type Source interface {
+	// Name identifies the source in metrics and logs
+	Name() string
+	// LabelNames are the names of the labels of the installations
+	LabelNames() []string
+	Discover(ctx context.Context) ([]Installation, error)
+}
+
+// SourceStatus holds the installations of the most recent successful run of a
+// source together with the outcome of the most recent run.
+type SourceStatus struct {
+	Name          string
+	Installations []Installation
+	LastAttempt   time.Time
+	LastSuccess   time.Time
+	Err           error
+}
+
+// Discoverer runs the sources and caches their installations.
+type Discoverer struct {
+	sources []Source
+
+	mu       sync.RWMutex
+	statuses map[string]SourceStatus
+}
+
+// New returns a discoverer for the given sources.
+func New(sources ...Source) *Discoverer {
+	return &Discoverer{
+		sources:  sources,
+		statuses: make(map[string]SourceStatus, len(sources)),
+	}
+}
+
+// Refresh runs all sources. Sources that fail keep their previously discovered
+// installations. The returned error joins the errors of all failed sources.
+func (d *Discoverer) Refresh(ctx context.Context) error {
+	var errs []error
+
+	for _, source := range d.sources {
+		installations, err := source.Discover(ctx)
+		now := time.Now()
+
+		d.mu.Lock()
+		status := d.statuses[source.Name()]
+		status.Name = source.Name()
+		status.LastAttempt = now
+		status.Err = err
+		if err == nil {
+			status.LastSuccess = now
+			status.Installations = dedup(source.Name(), installations)
+		}
+		d.statuses[source.Name()] = status
+		d.mu.Unlock()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Sources returns the status of all sources in the order they were added.
+func (d *Discoverer) Sources() []SourceStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	statuses := make([]SourceStatus, 0, len(d.sources))
+	for _, source := range d.sources {
+		status, ok := d.statuses[source.Name()]
+		if !ok {
+			status = SourceStatus{Name: source.Name()}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Installations returns the installations of all sources.
+func (d *Discoverer) Installations() []Installation {
+	var installations []Installation
+	for _, status := range d.Sources() {
+		installations = append(installations, status.Installations...)
+	}
+	return installations
+}
+
+// LabelNames returns the sorted union of the label names of all sources.
+func (d *Discoverer) LabelNames() []string {
+	names := []string{}
+	for _, source := range d.sources {
+		for _, name := range source.LabelNames() {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dedup sets the source of the installations and drops duplicates, e.g. the
+// same image in several containers of a workload.
+func dedup(source string, installations []Installation) []Installation {
+	seen := make(map[string]bool, len(installations))
+	unique := make([]Installation, 0, len(installations))
+	for _, installation := range installations {
+		key := []string{installation.Product, installation.Version}
+		for _, name := range slices.Sorted(maps.Keys(installation.Labels)) {
+			key = append(key, name+"="+installation.Labels[name])
+		}
+		id := strings.Join(key, "\x00")
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		installation.Source = source
+		unique = append(unique, installation)
+	}
+	return unique
+}
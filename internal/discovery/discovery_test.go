@@ -0,0 +1,124 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiscovery(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Discovery Suite")
+}
+
+// fakeIdentifiers returns fixed identifiers and counts the lookups.
+type fakeIdentifiers struct {
+	products map[string]string
+	calls    int
+}
+
+func (f *fakeIdentifiers) GetIdentifiers(_ context.Context, identifierType string) (map[string]string, error) {
+	f.calls++
+	if identifierType != "purl" {
+		return nil, errors.New("unknown identifier type")
+	}
+	return f.products, nil
+}
+
+// fakeSource returns fixed installations or an error.
+type fakeSource struct {
+	installations []Installation
+	err           error
+}
+
+func (f *fakeSource) Name() string { return "fake" }
+
+func (f *fakeSource) LabelNames() []string { return []string{"workload", "namespace"} }
+
+func (f *fakeSource) Discover(context.Context) ([]Installation, error) {
+	return f.installations, f.err
+}
+
+var _ = Describe("Discovery Suite", func() {
+	Context("When parsing image references", func() {
+		DescribeTable("should split registry, repository and tag",
+			func(ref string, expected Image) {
+				Expect(ParseImage(ref)).To(Equal(expected))
+			},
+			Entry("official image", "postgres:15.4-alpine", Image{Repository: "postgres", Tag: "15.4-alpine"}),
+			Entry("docker hub", "docker.io/library/redis:7.2.1", Image{Repository: "redis", Tag: "7.2.1"}),
+			Entry("namespaced", "bitnami/postgresql:16.1.0", Image{Repository: "bitnami/postgresql", Tag: "16.1.0"}),
+			Entry("registry with port", "registry.local:5000/team/app:1.2@sha256:abc", Image{Registry: "registry.local:5000", Repository: "team/app", Tag: "1.2"}),
+			Entry("no tag", "nginx", Image{Repository: "nginx"}),
+		)
+
+		It("should extract the version of a tag", func() {
+			for tag, expected := range map[string]string{"15.4-alpine": "15.4", "v1.28.3": "1.28.3", "7": "7"} {
+				version, ok := TagVersion(tag)
+				Expect(ok).To(BeTrue())
+				Expect(version).To(Equal(expected))
+			}
+			_, ok := TagVersion("latest")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should return the package URL", func() {
+			Expect(ParseImage("redis:7").PURL()).To(Equal("pkg:docker/library/redis"))
+			Expect(ParseImage("ghcr.io/org/app:1").PURL()).To(Equal("pkg:docker/org/app"))
+		})
+	})
+
+	Context("When resolving products", func() {
+		It("should prefer configured mappings over the defaults", func() {
+			resolver := NewResolver(map[string]string{"registry.local/db": "mysql", "postgres": "postgresql-custom"}, nil)
+
+			for name, expected := range map[string]string{"postgres": "postgresql-custom", "bitnami/redis": "redis", "registry.local/db": "mysql"} {
+				product, ok := resolver.Name(name)
+				Expect(ok).To(BeTrue())
+				Expect(product).To(Equal(expected))
+			}
+			_, ok := resolver.Name("unknown")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should fall back to the purl identifiers and cache them", func() {
+			client := &fakeIdentifiers{products: map[string]string{"pkg:docker/library/memcached@1.6": "memcached"}}
+			resolver := NewResolver(nil, client)
+
+			product, version, ok := resolver.Image(context.Background(), "memcached:1.6.21-alpine")
+			Expect(ok).To(BeTrue())
+			Expect(product).To(Equal("memcached"))
+			Expect(version).To(Equal("1.6.21"))
+
+			_, _, ok = resolver.Image(context.Background(), "unknown:1.0")
+			Expect(ok).To(BeFalse())
+			_, _, ok = resolver.Image(context.Background(), "postgres:latest")
+			Expect(ok).To(BeFalse())
+			Expect(client.calls).To(Equal(1))
+		})
+	})
+
+	Context("When running sources", func() {
+		It("should deduplicate installations and keep them on errors", func() {
+			source := &fakeSource{installations: []Installation{
+				{Product: "redis", Version: "7.2", Labels: map[string]string{"namespace": "a", "workload": "x"}},
+				{Product: "redis", Version: "7.2", Labels: map[string]string{"workload": "x", "namespace": "a"}},
+				{Product: "redis", Version: "7.2", Labels: map[string]string{"namespace": "b", "workload": "x"}},
+			}}
+			discoverer := New(source)
+			Expect(discoverer.LabelNames()).To(Equal([]string{"namespace", "workload"}))
+
+			Expect(discoverer.Refresh(context.Background())).To(Succeed())
+			Expect(discoverer.Installations()).To(HaveLen(2))
+			Expect(discoverer.Installations()[0].Source).To(Equal("fake"))
+
+			source.err = errors.New("unavailable")
+			Expect(discoverer.Refresh(context.Background())).NotTo(Succeed())
+			Expect(discoverer.Installations()).To(HaveLen(2))
+			Expect(discoverer.Sources()[0].Err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,120 @@
+// Package docker discovers the images of the running containers of a Docker
+// Engine.
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+// Config holds the Docker discovery flags.
+type Config struct {
+	Host    string        `env:"HOST" help:"Docker Engine API, e.g. unix:///var/run/docker.sock or tcp://127.0.0.1:2375. Disabled if empty."`
+	Timeout time.Duration `env:"TIMEOUT" default:"10s" help:"Timeout of a request to the Docker Engine API."`
+}
+
+// container is the part of a container in the list response that is used.
+type container struct {
+	ID    string   `json:"Id"`
+	Names []string `json:"Names"`
+	Image string   `json:"Image"`
+}
+
+// Source discovers the images of the running containers.
+type Source struct {
+	baseURL  string
+	client   *http.Client
+	resolver *discovery.Resolver
+}
+
+// New returns a source for the Docker Engine at cfg.Host.
+func New(cfg Config, resolver *discovery.Resolver) (*Source, error) {
+	host, err := url.Parse(cfg.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker host: %w", err)
+	}
+
+	transport := &http.Transport{}
+	baseURL := ""
+	switch host.Scheme {
+	case "unix":
+		socket := host.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		}
+		// The host is ignored when dialing the socket
+		baseURL = "http://docker"
+	case "tcp", "http":
+		baseURL = "http://" + host.Host
+	case "https":
+		baseURL = "https://" + host.Host
+	default:
+		return nil, fmt.Errorf("unsupported docker host scheme %q", host.Scheme)
+	}
+
+	return &Source{
+		baseURL:  baseURL,
+		client:   &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		resolver: resolver,
+	}, nil
+}
+
+func (s *Source) Name() string { return "docker" }
+
+func (s *Source) LabelNames() []string { return []string{"container"} }
+
+func (s *Source) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/containers/json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Warn("Error while closing the response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list containers: %s", resp.Status)
+	}
+
+	var containers []container
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, fmt.Errorf("failed to decode containers: %w", err)
+	}
+
+	var installations []discovery.Installation
+	for _, c := range containers {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		product, version, ok := s.resolver.Image(ctx, c.Image)
+		if !ok {
+			slog.Debug("Skipping unknown image", "container", name, "image", c.Image)
+			continue
+		}
+		installations = append(installations, discovery.Installation{
+			Product: product,
+			Version: version,
+			Labels:  map[string]string{"container": name},
+		})
+	}
+
+	return installations, nil
+}
@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+func TestDocker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Docker Discovery Suite")
+}
+
+var _ = Describe("Docker Discovery Suite", func() {
+	var socket string
+
+	BeforeEach(func() {
+		// Socket paths are limited to about 100 characters, keep it short
+		dir, err := os.MkdirTemp("", "docker")
+		Expect(err).To(BeNil())
+		DeferCleanup(os.RemoveAll, dir)
+		socket = filepath.Join(dir, "docker.sock")
+
+		listener, err := net.Listen("unix", socket)
+		Expect(err).To(BeNil())
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /containers/json", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[
+  {"Id": "8dfafdbc3a40", "Names": ["/shop-db-1"], "Image": "postgres:15.4-alpine", "State": "running"},
+  {"Id": "9cd87474be90", "Names": ["/shop-cache-1"], "Image": "redis:7.2.1", "State": "running"},
+  {"Id": "4f66ad9a0b2e", "Names": ["/shop-web-1"], "Image": "nginx:latest", "State": "running"},
+  {"Id": "b2a7e1c0f3d4", "Names": ["/shop-api-1"], "Image": "sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741", "State": "running"}
+]`))
+		})
+
+		server := &http.Server{Handler: mux}
+		go func() { _ = server.Serve(listener) }()
+		DeferCleanup(server.Close)
+	})
+
+	It("should discover the images of the running containers", func() {
+		source, err := New(Config{Host: "unix://" + socket}, discovery.NewResolver(nil, nil))
+		Expect(err).To(BeNil())
+
+		installations, err := source.Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			discovery.Installation{Product: "postgresql", Version: "15.4", Labels: map[string]string{"container": "shop-db-1"}},
+			discovery.Installation{Product: "redis", Version: "7.2.1", Labels: map[string]string{"container": "shop-cache-1"}},
+		))
+	})
+
+	It("should fail when the engine is not reachable", func() {
+		source, err := New(Config{Host: "unix://" + socket + ".missing"}, discovery.NewResolver(nil, nil))
+		Expect(err).To(BeNil())
+
+		_, err = source.Discover(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject unsupported hosts", func() {
+		_, err := New(Config{Host: "ssh://docker@example.com"}, discovery.NewResolver(nil, nil))
+		Expect(err).To(HaveOccurred())
+	})
+})
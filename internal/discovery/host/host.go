@@ -0,0 +1,268 @@
+// Package host discovers the OS, kernel and package versions of the machine
+// the exporter runs on.
+package host
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+// Config holds the host discovery flags.
+type Config struct {
+	Enabled  bool     `env:"ENABLED" help:"Discover the OS, kernel and package versions of the host."`
+	Root     string   `env:"ROOT" default:"/" help:"Root of the host file system, e.g. /host if it is mounted into a container."`
+	Packages []string `env:"PACKAGES" default:"nginx,postgresql,openssl" help:"Packages looked up in the dpkg, apk and rpm databases, none if empty."`
+}
+
+// osProducts map the ID of os-release to its product.
+var osProducts = map[string]string{
+	"almalinux": "almalinux",
+	"alpine":    "alpine-linux",
+	"amzn":      "amazon-linux",
+	"debian":    "debian",
+	"rhel":      "rhel",
+	"rocky":     "rocky-linux",
+	"ubuntu":    "ubuntu",
+}
+
+// osReleasePaths are read in order, as described in os-release(5).
+var osReleasePaths = []string{"etc/os-release", "usr/lib/os-release"}
+
+var (
+	// versionPattern matches the upstream version of a package version such
+	// as "1:3.0.2-0ubuntu1.12" or "1.24.0-r7".
+	versionPattern = regexp.MustCompile(`^(?:\d+:)?(\d+(\.\d+)*)`)
+	// suffixPattern matches the major version suffix of package names like
+	// "postgresql-15" or "postgresql15".
+	suffixPattern = regexp.MustCompile(`^-?\d+$`)
+)
+
+// packageDatabases are the package databases read from the file system.
+var packageDatabases = []struct {
+	path  string
+	parse func(data []byte) []pkg
+}{
+	{"var/lib/dpkg/status", parseDpkg},
+	{"lib/apk/db/installed", parseApk},
+}
+
+// pkg is an installed package of a package database.
+type pkg struct {
+	Name    string
+	Version string
+}
+
+// Source discovers the OS of os-release, the kernel release and the versions
+// of the tracked packages installed by dpkg, apk or rpm.
+type Source struct {
+	root     string
+	packages []string
+	resolver *discovery.Resolver
+
+	// rpmQuery lists the name and version of all rpm packages, one per line
+	rpmQuery func(ctx context.Context, root string) ([]byte, error)
+}
+
+// New returns a source for the host file system at root.
+func New(cfg Config, resolver *discovery.Resolver) *Source {
+	return &Source{root: cfg.Root, packages: cfg.Packages, resolver: resolver, rpmQuery: rpmQuery}
+}
+
+func (s *Source) Name() string { return "host" }
+
+func (s *Source) LabelNames() []string { return []string{"component", "package"} }
+
+func (s *Source) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	var installations []discovery.Installation
+
+	product, version, err := s.osRelease()
+	if err != nil {
+		return nil, err
+	}
+	if product != "" {
+		installations = append(installations, component(product, version, "os", ""))
+	}
+
+	release, err := os.ReadFile(filepath.Join(s.root, "proc/sys/kernel/osrelease"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read kernel release: %w", err)
+	}
+	if version, ok := discovery.TagVersion(strings.TrimSpace(string(release))); ok {
+		installations = append(installations, component("linux", version, "kernel", ""))
+	}
+
+	if len(s.packages) == 0 {
+		return installations, nil
+	}
+	pkgs, err := s.installedPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pkgs {
+		name, ok := s.tracked(p.Name)
+		if !ok {
+			continue
+		}
+		product, ok := s.resolver.Name(name)
+		match := versionPattern.FindStringSubmatch(p.Version)
+		if !ok || match == nil {
+			slog.Debug("Skipping unknown package", "package", p.Name, "version", p.Version)
+			continue
+		}
+		installations = append(installations, component(product, match[1], "package", p.Name))
+	}
+
+	return installations, nil
+}
+
+// osRelease returns the product and version of the first os-release file, empty if
+// there is none or the OS is unknown.
+func (s *Source) osRelease() (product, version string, err error) {
+	for _, path := range osReleasePaths {
+		data, err := os.ReadFile(filepath.Join(s.root, path))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read os-release: %w", err)
+		}
+
+		fields := parseOSRelease(data)
+		product, ok := osProducts[fields["ID"]]
+		if !ok || fields["VERSION_ID"] == "" {
+			slog.Debug("Skipping unknown OS", "id", fields["ID"], "version_id", fields["VERSION_ID"])
+			return "", "", nil
+		}
+		return product, fields["VERSION_ID"], nil
+	}
+	return "", "", nil
+}
+
+// installedPackages returns the packages of the dpkg and apk databases, and of
+// rpm if the host has an rpm database and the rpm binary is available.
+func (s *Source) installedPackages(ctx context.Context) ([]pkg, error) {
+	var pkgs []pkg
+
+	for _, db := range packageDatabases {
+		data, err := os.ReadFile(filepath.Join(s.root, db.path))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read package database: %w", err)
+		}
+		pkgs = append(pkgs, db.parse(data)...)
+	}
+
+	if _, err := os.Stat(filepath.Join(s.root, "var/lib/rpm")); err == nil {
+		out, err := s.rpmQuery(ctx, s.root)
+		if err != nil {
+			slog.Warn("Failed to query rpm database", "error", err)
+		}
+		pkgs = append(pkgs, parseRpm(out)...)
+	}
+
+	return pkgs, nil
+}
+
+// tracked returns the tracked package a package name belongs to, including
+// names with a major version suffix like "postgresql-15".
+func (s *Source) tracked(name string) (string, bool) {
+	for _, p := range s.packages {
+		if rest, ok := strings.CutPrefix(name, p); ok && (rest == "" || suffixPattern.MatchString(rest)) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func component(product, version, name, pkg string) discovery.Installation {
+	return discovery.Installation{
+		Product: product,
+		Version: version,
+		Labels:  map[string]string{"component": name, "package": pkg},
+	}
+}
+
+// parseOSRelease returns the variables of an os-release file with their
+// quotes removed.
+func parseOSRelease(data []byte) map[string]string {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		fields[key] = strings.Trim(value, `"'`)
+	}
+	return fields
+}
+
+// parseDpkg returns the installed packages of a dpkg status file, whose
+// paragraphs are separated by blank lines.
+func parseDpkg(data []byte) []pkg {
+	var pkgs []pkg
+	for _, paragraph := range strings.Split(string(data), "\n\n") {
+		fields := map[string]string{}
+		for _, line := range strings.Split(paragraph, "\n") {
+			if key, value, ok := strings.Cut(line, ": "); ok && !strings.HasPrefix(line, " ") {
+				fields[key] = value
+			}
+		}
+		if fields["Package"] != "" && strings.HasSuffix(fields["Status"], " installed") {
+			pkgs = append(pkgs, pkg{Name: fields["Package"], Version: fields["Version"]})
+		}
+	}
+	return pkgs
+}
+
+// parseApk returns the packages of an apk database, whose entries are
+// separated by blank lines and hold the name in P: and the version in V:.
+func parseApk(data []byte) []pkg {
+	var pkgs []pkg
+	var p pkg
+	for _, line := range strings.Split(string(data)+"\n", "\n") {
+		switch {
+		case line == "":
+			if p.Name != "" {
+				pkgs = append(pkgs, p)
+			}
+			p = pkg{}
+		case strings.HasPrefix(line, "P:"):
+			p.Name = line[2:]
+		case strings.HasPrefix(line, "V:"):
+			p.Version = line[2:]
+		}
+	}
+	return pkgs
+}
+
+// parseRpm returns the packages of the "NAME VERSION" lines of rpmQuery.
+func parseRpm(data []byte) []pkg {
+	var pkgs []pkg
+	for _, line := range strings.Split(string(data), "\n") {
+		if name, version, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+			pkgs = append(pkgs, pkg{Name: name, Version: version})
+		}
+	}
+	return pkgs
+}
+
+// rpmQuery lists the rpm packages with the rpm binary, as the database is
+// not stored in a format that can be read without it.
+func rpmQuery(ctx context.Context, root string) ([]byte, error) {
+	return exec.CommandContext(ctx, "rpm", "--root", root, "-qa", "--queryformat", "%{NAME} %{VERSION}\n").Output()
+}
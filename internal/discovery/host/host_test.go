@@ -0,0 +1,123 @@
+package host
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+func TestHost(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Host Discovery Suite")
+}
+
+// writeFiles creates the files below root, creating directories as needed.
+func writeFiles(root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+}
+
+const dpkgStatus = `Package: nginx-core
+Status: install ok installed
+Version: 1.18.0-6ubuntu14.4
+
+Package: openssl
+Status: install ok installed
+Priority: optional
+Version: 3.0.2-0ubuntu1.12
+Description: Secure Sockets Layer toolkit
+ This package contains the openssl binary.
+ Version: 0.1
+
+Package: postgresql-14
+Status: install ok installed
+Version: 14.10-0ubuntu0.22.04.1
+
+Package: postgresql-client-common
+Status: install ok installed
+Version: 238
+
+Package: nginx
+Status: deinstall ok config-files
+Version: 1.18.0-6ubuntu14.4
+`
+
+var _ = Describe("Host Discovery Suite", func() {
+	resolver := discovery.NewResolver(nil, nil)
+	packages := []string{"nginx", "postgresql", "openssl"}
+
+	It("should discover the OS, kernel and packages of a Debian based host", func() {
+		root := GinkgoT().TempDir()
+		writeFiles(root, map[string]string{
+			"etc/os-release":            "NAME=\"Ubuntu\"\nVERSION_ID=\"22.04\"\nID=ubuntu\nID_LIKE=debian\n",
+			"proc/sys/kernel/osrelease": "5.15.0-91-generic\n",
+			"var/lib/dpkg/status":       dpkgStatus,
+		})
+
+		installations, err := New(Config{Root: root, Packages: packages}, resolver).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			component("ubuntu", "22.04", "os", ""),
+			component("linux", "5.15.0", "kernel", ""),
+			component("openssl", "3.0.2", "package", "openssl"),
+			component("postgresql", "14.10", "package", "postgresql-14"),
+		))
+	})
+
+	It("should discover the packages of an Alpine host", func() {
+		root := GinkgoT().TempDir()
+		writeFiles(root, map[string]string{
+			"usr/lib/os-release":   "ID=alpine\nVERSION_ID=3.18.4\n",
+			"lib/apk/db/installed": "C:Q1abc=\nP:nginx\nV:1.24.0-r7\n\nP:postgresql15\nV:15.5-r0\n\nP:musl\nV:1.2.4-r2\n",
+		})
+
+		installations, err := New(Config{Root: root, Packages: packages}, resolver).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			component("alpine-linux", "3.18.4", "os", ""),
+			component("nginx", "1.24.0", "package", "nginx"),
+			component("postgresql", "15.5", "package", "postgresql15"),
+		))
+	})
+
+	It("should query the rpm database", func() {
+		root := GinkgoT().TempDir()
+		writeFiles(root, map[string]string{
+			"etc/os-release":           "ID=\"amzn\"\nVERSION_ID=\"2023\"\n",
+			"var/lib/rpm/rpmdb.sqlite": "",
+		})
+		source := New(Config{Root: root, Packages: packages}, resolver)
+		source.rpmQuery = func(ctx context.Context, root string) ([]byte, error) {
+			return []byte("bash 5.2.15\nnginx 1.24.0\nopenssl 3.0.8\n"), nil
+		}
+
+		installations, err := source.Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			component("amazon-linux", "2023", "os", ""),
+			component("nginx", "1.24.0", "package", "nginx"),
+			component("openssl", "3.0.8", "package", "openssl"),
+		))
+	})
+
+	It("should skip unknown operating systems", func() {
+		root := GinkgoT().TempDir()
+		writeFiles(root, map[string]string{"etc/os-release": "ID=gentoo\nVERSION_ID=2.14\n"})
+
+		installations, err := New(Config{Root: root}, resolver).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(BeEmpty())
+	})
+})
@@ -0,0 +1,63 @@
+package discovery
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Image is a parsed container image reference.
+type Image struct {
+	Registry   string // Empty for Docker Hub
+	Repository string // Without the registry and the "library/" prefix of official images
+	Tag        string
+}
+
+// ParseImage parses an image reference such as "postgres:15.4-alpine" or
+// "ghcr.io/org/app:1.2@sha256:...". Digests are ignored.
+func ParseImage(ref string) Image {
+	image := Image{}
+
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		image.Tag = ref[i+1:]
+		ref = ref[:i]
+	}
+
+	// The first component is a registry if it looks like a host name
+	if first, rest, ok := strings.Cut(ref, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		image.Registry = first
+		ref = rest
+	}
+	if image.Registry == "docker.io" || image.Registry == "index.docker.io" {
+		image.Registry = ""
+	}
+	if image.Registry == "" {
+		ref = strings.TrimPrefix(ref, "library/")
+	}
+
+	image.Repository = strings.ToLower(ref)
+	return image
+}
+
+// PURL returns the package URL of the image without its version.
+func (i Image) PURL() string {
+	repository := i.Repository
+	if i.Registry == "" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return "pkg:docker/" + repository
+}
+
+var tagVersionPattern = regexp.MustCompile(`^v?(\d+(\.\d+)*)`)
+
+// TagVersion returns the version at the start of an image tag, e.g. "15.4" of
+// "15.4-alpine". Tags such as "latest" have no version.
+func TagVersion(tag string) (string, bool) {
+	match := tagVersionPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
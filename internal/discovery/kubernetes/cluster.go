@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// osImageProducts map the prefix of a node's OS image to its product, the
+// first matching prefix wins. OS images with an empty product are skipped.
+var osImageProducts = []struct {
+	prefix  string
+	product string
+}{
+	{"Ubuntu", "ubuntu"},
+	// Versioned by the OpenShift release, e.g. 414.92 for 4.14, not by RHEL
+	{"Red Hat Enterprise Linux CoreOS", ""},
+	{"Red Hat Enterprise Linux", "rhel"},
+	{"Amazon Linux", "amazon-linux"},
+	{"Debian GNU/Linux", "debian"},
+	{"Rocky Linux", "rocky-linux"},
+	{"AlmaLinux", "almalinux"},
+}
+
+// runtimeProducts map the scheme of a node's container runtime version, e.g.
+// "containerd://1.7.2", to its product.
+var runtimeProducts = map[string]string{
+	"containerd": "containerd",
+	"docker":     "docker-engine",
+}
+
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// ClusterSource discovers the Kubernetes version of the API server and the
+// Kubernetes, OS, kernel and container runtime versions of every node.
+type ClusterSource struct {
+	client kubernetes.Interface
+}
+
+// NewClusterSource returns a source for the cluster of client.
+func NewClusterSource(client kubernetes.Interface) *ClusterSource {
+	return &ClusterSource{client: client}
+}
+
+func (s *ClusterSource) Name() string { return "kubernetes-cluster" }
+
+func (s *ClusterSource) LabelNames() []string { return []string{"component", "node"} }
+
+func (s *ClusterSource) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	var installations []discovery.Installation
+
+	serverVersion, err := s.client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	if version, ok := discovery.TagVersion(serverVersion.GitVersion); ok {
+		installations = append(installations, component("kubernetes", version, "apiserver", ""))
+	}
+
+	nodes, err := s.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		info := node.Status.NodeInfo
+
+		if version, ok := discovery.TagVersion(info.KubeletVersion); ok {
+			installations = append(installations, component("kubernetes", version, "kubelet", node.Name))
+		}
+		if product, version, ok := osImage(info.OSImage); ok {
+			installations = append(installations, component(product, version, "os", node.Name))
+		} else {
+			slog.Debug("Skipping unknown OS image", "node", node.Name, "os_image", info.OSImage)
+		}
+		if version, ok := discovery.TagVersion(info.KernelVersion); ok && info.OperatingSystem != "windows" {
+			installations = append(installations, component("linux", version, "kernel", node.Name))
+		}
+		if scheme, rest, ok := strings.Cut(info.ContainerRuntimeVersion, "://"); ok && runtimeProducts[scheme] != "" {
+			if version, ok := discovery.TagVersion(rest); ok {
+				installations = append(installations, component(runtimeProducts[scheme], version, "container-runtime", node.Name))
+			}
+		}
+	}
+
+	return installations, nil
+}
+
+func component(product, version, name, node string) discovery.Installation {
+	return discovery.Installation{
+		Product: product,
+		Version: version,
+		Labels:  map[string]string{"component": name, "node": node},
+	}
+}
+
+// osImage returns the product and version of an OS image such as
+// "Ubuntu 22.04.3 LTS" or "Amazon Linux 2023.2.20231113".
+func osImage(image string) (product, version string, ok bool) {
+	for _, os := range osImageProducts {
+		rest, found := strings.CutPrefix(image, os.prefix)
+		if !found {
+			continue
+		}
+		if os.product == "" {
+			return "", "", false
+		}
+		version = versionPattern.FindString(rest)
+		return os.product, version, version != ""
+	}
+	return "", "", false
+}
@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// helmSelector selects the secrets of the deployed revision of every Helm v3
+// release.
+const helmSelector = "owner=helm,status=deployed"
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// helmRelease is a Helm release reduced to its chart metadata.
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Chart     struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// HelmSource discovers the app versions of the charts of Helm releases, read
+// from the release secrets Helm v3 stores in the namespace of a release.
+type HelmSource struct {
+	client     kubernetes.Interface
+	namespaces []string
+	resolver   *discovery.Resolver
+}
+
+// NewHelmSource returns a source for the Helm releases in the given
+// namespaces, all if empty.
+func NewHelmSource(client kubernetes.Interface, namespaces []string, resolver *discovery.Resolver) *HelmSource {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	return &HelmSource{client: client, namespaces: namespaces, resolver: resolver}
+}
+
+func (s *HelmSource) Name() string { return "helm" }
+
+func (s *HelmSource) LabelNames() []string { return []string{"namespace", "release"} }
+
+// Discover maps the chart name of every deployed release to a product and
+// its appVersion to the installed version. Releases that cannot be decoded or
+// whose chart is unknown are skipped.
+func (s *HelmSource) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	var installations []discovery.Installation
+
+	for _, namespace := range s.namespaces {
+		secrets, err := s.client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: helmSelector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list helm release secrets: %w", err)
+		}
+		for _, secret := range secrets.Items {
+			release, err := decodeRelease(secret.Data["release"])
+			if err != nil {
+				slog.Warn("Skipping invalid helm release", "namespace", secret.Namespace, "secret", secret.Name, "error", err)
+				continue
+			}
+
+			chart := release.Chart.Metadata
+			product, ok := s.resolver.Name(chart.Name)
+			version, hasVersion := discovery.TagVersion(chart.AppVersion)
+			if !ok || !hasVersion {
+				slog.Debug("Skipping unknown chart", "namespace", secret.Namespace, "release", release.Name, "chart", chart.Name, "app_version", chart.AppVersion)
+				continue
+			}
+			installations = append(installations, discovery.Installation{
+				Product: product,
+				Version: version,
+				Labels:  map[string]string{"namespace": secret.Namespace, "release": release.Name},
+			})
+		}
+	}
+
+	return installations, nil
+}
+
+// decodeRelease decodes the release of a secret, which Helm stores as base64
+// encoded, usually gzipped JSON.
+func decodeRelease(data []byte) (*helmRelease, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	if bytes.HasPrefix(decoded, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+		defer reader.Close()
+		if decoded, err = io.ReadAll(reader); err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+	}
+
+	var release helmRelease
+	if err := json.Unmarshal(decoded, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &release, nil
+}
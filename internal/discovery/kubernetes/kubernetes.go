@@ -0,0 +1,137 @@
+// Package kubernetes discovers the installed versions of products in a
+// Kubernetes cluster.
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Config holds the Kubernetes discovery flags.
+type Config struct {
+	Enabled    bool     `env:"ENABLED" help:"Discover the images of the workloads in a Kubernetes cluster."`
+	Cluster    bool     `env:"CLUSTER" help:"Discover the Kubernetes version of the API server and the Kubernetes, OS, kernel and container runtime versions of the nodes."`
+	Helm       bool     `env:"HELM" help:"Discover the app versions of the charts of Helm releases."`
+	Kubeconfig string   `env:"KUBECONFIG" help:"Kubeconfig file, the default loading rules and the in-cluster configuration are used if empty."`
+	Namespaces []string `env:"NAMESPACES" help:"Namespaces to discover, all if empty."`
+}
+
+// NewClient returns a client for the cluster of the kubeconfig, or the cluster
+// the exporter runs in.
+func NewClient(cfg Config) (kubernetes.Interface, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = cfg.Kubeconfig
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// WorkloadSource discovers the images of Deployments, StatefulSets and Pods.
+// Pods are attributed to the workload that controls them.
+type WorkloadSource struct {
+	client     kubernetes.Interface
+	namespaces []string
+	resolver   *discovery.Resolver
+}
+
+// NewWorkloadSource returns a source for the workloads in the given
+// namespaces, all if empty.
+func NewWorkloadSource(client kubernetes.Interface, namespaces []string, resolver *discovery.Resolver) *WorkloadSource {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	return &WorkloadSource{client: client, namespaces: namespaces, resolver: resolver}
+}
+
+func (s *WorkloadSource) Name() string { return "kubernetes" }
+
+func (s *WorkloadSource) LabelNames() []string { return []string{"namespace", "workload"} }
+
+func (s *WorkloadSource) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	var installations []discovery.Installation
+
+	for _, namespace := range s.namespaces {
+		// Workloads whose Pods are already covered, by namespace/kind/name
+		workloads := make(map[string]bool)
+
+		deployments, err := s.client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		for _, deployment := range deployments.Items {
+			workloads[deployment.Namespace+"/Deployment/"+deployment.Name] = true
+			installations = append(installations, s.containers(ctx, deployment.Namespace, "deployment/"+deployment.Name, deployment.Spec.Template.Spec.Containers)...)
+		}
+
+		statefulSets, err := s.client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+		}
+		for _, statefulSet := range statefulSets.Items {
+			workloads[statefulSet.Namespace+"/StatefulSet/"+statefulSet.Name] = true
+			installations = append(installations, s.containers(ctx, statefulSet.Namespace, "statefulset/"+statefulSet.Name, statefulSet.Spec.Template.Spec.Containers)...)
+		}
+
+		pods, err := s.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			kind, name := podWorkload(&pod)
+			if workloads[pod.Namespace+"/"+kind+"/"+name] {
+				continue
+			}
+			installations = append(installations, s.containers(ctx, pod.Namespace, strings.ToLower(kind)+"/"+name, pod.Spec.Containers)...)
+		}
+	}
+
+	return installations, nil
+}
+
+// containers resolves the images of the containers of a workload.
+func (s *WorkloadSource) containers(ctx context.Context, namespace, workload string, containers []corev1.Container) []discovery.Installation {
+	var installations []discovery.Installation
+	for _, container := range containers {
+		product, version, ok := s.resolver.Image(ctx, container.Image)
+		if !ok {
+			slog.Debug("Skipping unknown image", "namespace", namespace, "workload", workload, "image", container.Image)
+			continue
+		}
+		installations = append(installations, discovery.Installation{
+			Product: product,
+			Version: version,
+			Labels:  map[string]string{"namespace": namespace, "workload": workload},
+		})
+	}
+	return installations
+}
+
+// podWorkload returns the kind and name of the workload controlling the Pod.
+// Pods of a ReplicaSet belong to the Deployment the ReplicaSet is named after.
+func podWorkload(pod *corev1.Pod) (kind, name string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod", pod.Name
+	}
+	if owner.Kind == "ReplicaSet" && owner.APIVersion == appsv1.SchemeGroupVersion.String() {
+		if i := strings.LastIndex(owner.Name, "-"); i > 0 {
+			return "Deployment", owner.Name[:i]
+		}
+	}
+	return owner.Kind, owner.Name
+}
@@ -0,0 +1,205 @@
+package kubernetes
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetes(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Kubernetes Discovery Suite")
+}
+
+func podSpec(images ...string) corev1.PodSpec {
+	spec := corev1.PodSpec{}
+	for _, image := range images {
+		spec.Containers = append(spec.Containers, corev1.Container{Image: image})
+	}
+	return spec
+}
+
+func pod(namespace, name string, owner *metav1.OwnerReference, images ...string) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       podSpec(images...),
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if owner != nil {
+		controller := true
+		owner.Controller = &controller
+		p.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return p
+}
+
+// helmSecret returns the secret Helm v3 stores for a revision of a release.
+func helmSecret(namespace, name, chart, appVersion, status string, revision int) *corev1.Secret {
+	release, err := json.Marshal(map[string]any{
+		"name":      name,
+		"namespace": namespace,
+		"version":   revision,
+		"info":      map[string]any{"status": status},
+		"chart":     map[string]any{"metadata": map[string]any{"name": chart, "version": "1.0.0", "appVersion": appVersion}},
+	})
+	Expect(err).To(BeNil())
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write(release)
+	Expect(err).To(BeNil())
+	Expect(writer.Close()).To(Succeed())
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, revision),
+			Labels:    map[string]string{"owner": "helm", "name": name, "status": status},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(compressed.Bytes()))},
+	}
+}
+
+var _ = Describe("Kubernetes Discovery Suite", func() {
+	objects := []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api"},
+			Spec:       appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: podSpec("nginx:1.25.3-alpine", "ghcr.io/shop/api:2.0.0")}},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db"},
+			Spec:       appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: podSpec("postgres:15.4-alpine")}},
+		},
+		// Covered by their workloads
+		pod("shop", "api-6d4b9c7f8-x2k4p", &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "api-6d4b9c7f8"}, "nginx:1.25.3-alpine"),
+		pod("shop", "db-0", &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db"}, "postgres:15.4-alpine"),
+		pod("monitoring", "fluentd-abcde", &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "fluentd"}, "redis:7.2.1"),
+		pod("default", "debug", nil, "python:3.12-slim"),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "migration"},
+			Spec:       podSpec("node:18.19.0"),
+			Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+
+	It("should discover the images of all workloads", func() {
+		source := NewWorkloadSource(fake.NewClientset(objects...), nil, discovery.NewResolver(nil, nil))
+
+		installations, err := source.Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			discovery.Installation{Product: "nginx", Version: "1.25.3", Labels: map[string]string{"namespace": "shop", "workload": "deployment/api"}},
+			discovery.Installation{Product: "postgresql", Version: "15.4", Labels: map[string]string{"namespace": "shop", "workload": "statefulset/db"}},
+			discovery.Installation{Product: "redis", Version: "7.2.1", Labels: map[string]string{"namespace": "monitoring", "workload": "daemonset/fluentd"}},
+			discovery.Installation{Product: "python", Version: "3.12", Labels: map[string]string{"namespace": "default", "workload": "pod/debug"}},
+		))
+	})
+
+	It("should only discover the given namespaces", func() {
+		source := NewWorkloadSource(fake.NewClientset(objects...), []string{"monitoring"}, discovery.NewResolver(nil, nil))
+
+		installations, err := source.Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(HaveLen(1))
+		Expect(installations[0].Product).To(Equal("redis"))
+	})
+
+	It("should discover the app versions of deployed helm releases", func() {
+		client := fake.NewClientset(
+			helmSecret("ingress-nginx", "ingress", "ingress-nginx", "1.9.4", "deployed", 3),
+			helmSecret("ingress-nginx", "ingress", "ingress-nginx", "1.8.1", "superseded", 2),
+			helmSecret("cert-manager", "cert-manager", "cert-manager", "v1.13.2", "deployed", 1),
+			helmSecret("argocd", "argocd", "argo-cd", "v2.9.3", "deployed", 1),
+			helmSecret("monitoring", "grafana", "grafana", "10.2.2", "deployed", 1),
+			helmSecret("shop", "shop", "shop-api", "2.0.0", "deployed", 1),
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "sh.helm.release.v1.broken.v1", Labels: map[string]string{"owner": "helm", "status": "deployed"}},
+				Data:       map[string][]byte{"release": []byte("not base64")},
+			},
+		)
+
+		installations, err := NewHelmSource(client, nil, discovery.NewResolver(nil, nil)).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			discovery.Installation{Product: "ingress-nginx", Version: "1.9.4", Labels: map[string]string{"namespace": "ingress-nginx", "release": "ingress"}},
+			discovery.Installation{Product: "cert-manager", Version: "1.13.2", Labels: map[string]string{"namespace": "cert-manager", "release": "cert-manager"}},
+			discovery.Installation{Product: "argo-cd", Version: "2.9.3", Labels: map[string]string{"namespace": "argocd", "release": "argocd"}},
+			discovery.Installation{Product: "grafana", Version: "10.2.2", Labels: map[string]string{"namespace": "monitoring", "release": "grafana"}},
+		))
+	})
+
+	It("should discover the versions of the cluster and its nodes", func() {
+		client := fake.NewClientset(
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "ip-10-0-1-12"},
+				Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+					KubeletVersion:          "v1.28.3-eks-4f4795d",
+					OSImage:                 "Amazon Linux 2",
+					KernelVersion:           "5.10.198-187.748.amzn2.x86_64",
+					ContainerRuntimeVersion: "containerd://1.7.2",
+					OperatingSystem:         "linux",
+				}},
+			},
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+				Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+					KubeletVersion:          "v1.27.8",
+					OSImage:                 "Ubuntu 22.04.3 LTS",
+					KernelVersion:           "5.15.0-1051-aws",
+					ContainerRuntimeVersion: "cri-o://1.27.1",
+					OperatingSystem:         "linux",
+				}},
+			},
+		)
+		client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.28.4-eks-8cb36c9"}
+
+		installations, err := NewClusterSource(client).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			component("kubernetes", "1.28.4", "apiserver", ""),
+			component("kubernetes", "1.28.3", "kubelet", "ip-10-0-1-12"),
+			component("amazon-linux", "2", "os", "ip-10-0-1-12"),
+			component("linux", "5.10.198", "kernel", "ip-10-0-1-12"),
+			component("containerd", "1.7.2", "container-runtime", "ip-10-0-1-12"),
+			component("kubernetes", "1.27.8", "kubelet", "worker-1"),
+			component("ubuntu", "22.04.3", "os", "worker-1"),
+			component("linux", "5.15.0", "kernel", "worker-1"),
+		))
+	})
+
+	It("should map OS images to products", func() {
+		for image, expected := range map[string][2]string{
+			"Red Hat Enterprise Linux 9.2 (Plow)": {"rhel", "9.2"},
+			"Amazon Linux 2023.2.20231113":        {"amazon-linux", "2023.2.20231113"},
+			"Debian GNU/Linux 12 (bookworm)":      {"debian", "12"},
+		} {
+			product, version, ok := osImage(image)
+			Expect(ok).To(BeTrue())
+			Expect([2]string{product, version}).To(Equal(expected))
+		}
+		_, _, ok := osImage("Bottlerocket OS 1.15.1 (aws-k8s-1.28)")
+		Expect(ok).To(BeFalse())
+		_, _, ok = osImage("Red Hat Enterprise Linux CoreOS 414.92.202310210434-0 (Plow)")
+		Expect(ok).To(BeFalse())
+	})
+})
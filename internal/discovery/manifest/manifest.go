@@ -0,0 +1,284 @@
+// Package manifest discovers the runtime versions pinned in the manifests of
+// source repositories, e.g. the go directive of go.mod files.
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+// Config holds the manifest discovery flags.
+type Config struct {
+	Path string `env:"PATH" help:"Directory of source repositories whose manifests are scanned on every refresh. Disabled if empty."`
+}
+
+// pin is a runtime version or an image found in a manifest. Runtime is
+// resolved to a product like an image name, e.g. "node" to nodejs.
+type pin struct {
+	Runtime string
+	Version string
+	Image   string
+}
+
+// parser extracts the pins of a manifest file.
+type parser func(data []byte) []pin
+
+// skipDirs are never descended into, they contain dependencies, not sources.
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+var (
+	versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+	goPattern      = regexp.MustCompile(`(?m)^go\s+(\d+(\.\d+)*)\s*$`)
+	gemfilePattern = regexp.MustCompile(`(?m)^\s*ruby\s+['"]([^'"]+)['"]`)
+	fromPattern    = regexp.MustCompile(`(?im)^\s*FROM\s+(?:--\S+\s+)*(\S+)(?:\s+AS\s+(\S+))?`)
+	pomPattern     = regexp.MustCompile(`<(java\.version|maven\.compiler\.release|maven\.compiler\.source)>\s*([^<\s]+)\s*</`)
+)
+
+// parserFor returns the parser for a file name, nil if it is not a manifest.
+func parserFor(name string) parser {
+	switch {
+	case name == "go.mod":
+		return parseGoMod
+	case name == "package.json":
+		return parsePackageJSON
+	case name == "Dockerfile" || strings.HasPrefix(name, "Dockerfile.") || strings.HasSuffix(name, ".Dockerfile"):
+		return parseDockerfile
+	case name == ".python-version":
+		return versionFile("python")
+	case name == ".nvmrc" || name == ".node-version":
+		return versionFile("node")
+	case name == ".ruby-version":
+		return versionFile("ruby")
+	case name == ".tool-versions":
+		return parseToolVersions
+	case name == "pom.xml":
+		return parsePom
+	case name == "Gemfile":
+		return parseGemfile
+	}
+	return nil
+}
+
+// Source discovers the pins of the manifests in a directory tree. Every
+// installation is labeled with its repository, the closest directory with a
+// .git entry or else the top-level directory, and the file path within it.
+type Source struct {
+	root     string
+	resolver *discovery.Resolver
+}
+
+// New returns a source for the repositories below root.
+func New(root string, resolver *discovery.Resolver) *Source {
+	return &Source{root: root, resolver: resolver}
+}
+
+func (s *Source) Name() string { return "manifest" }
+
+func (s *Source) LabelNames() []string { return []string{"file", "repo"} }
+
+func (s *Source) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	var installations []discovery.Installation
+
+	// Repository directory of every visited directory, relative to root.
+	// Directories outside of a repository map to an empty string.
+	repos := map[string]string{}
+
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && (skipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			repos[rel] = repoOf(path, rel, repos)
+			return nil
+		}
+
+		parse := parserFor(d.Name())
+		if parse == nil {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		repo := repos[filepath.ToSlash(filepath.Dir(rel))]
+		file := rel
+		if repo != "" && repo != "." {
+			file = strings.TrimPrefix(rel, repo+"/")
+		}
+
+		for _, p := range parse(data) {
+			installation, ok := s.resolve(ctx, p)
+			if !ok {
+				slog.Debug("Skipping unknown runtime", "file", rel, "runtime", p.Runtime, "version", p.Version)
+				continue
+			}
+			installation.Labels = map[string]string{"repo": s.repoName(repo), "file": file}
+			installations = append(installations, installation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return installations, nil
+}
+
+// repoName returns the name of a repository directory, the name of the root
+// directory if it is a repository itself.
+func (s *Source) repoName(repo string) string {
+	if repo != "." {
+		return repo
+	}
+	abs, err := filepath.Abs(s.root)
+	if err != nil {
+		return repo
+	}
+	return filepath.Base(abs)
+}
+
+// resolve maps a pin to a product.
+func (s *Source) resolve(ctx context.Context, p pin) (discovery.Installation, bool) {
+	if p.Image != "" {
+		product, version, ok := s.resolver.Image(ctx, p.Image)
+		return discovery.Installation{Product: product, Version: version}, ok
+	}
+
+	product, ok := s.resolver.Name(p.Runtime)
+	return discovery.Installation{Product: product, Version: p.Version}, ok && p.Version != ""
+}
+
+// repoOf returns the repository directory of the directory at path: the
+// directory itself if it contains a .git entry, else the repository of its
+// parent. Top-level directories outside of a repository are repositories of
+// their own.
+func repoOf(path, rel string, repos map[string]string) string {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		return rel
+	}
+	if rel == "." {
+		return ""
+	}
+	parent := filepath.ToSlash(filepath.Dir(rel))
+	if parent == "." && repos["."] == "" {
+		return rel
+	}
+	return repos[parent]
+}
+
+// firstVersion returns the first version number in a constraint such as
+// ">=18.17" or "~> 3.1", empty if there is none.
+func firstVersion(constraint string) string {
+	return versionPattern.FindString(constraint)
+}
+
+func parseGoMod(data []byte) []pin {
+	match := goPattern.FindSubmatch(data)
+	if match == nil {
+		return nil
+	}
+	return []pin{{Runtime: "go", Version: string(match[1])}}
+}
+
+func parsePackageJSON(data []byte) []pin {
+	var pkg struct {
+		Engines map[string]string `json:"engines"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Engines["node"] == "" {
+		return nil
+	}
+	return []pin{{Runtime: "node", Version: firstVersion(pkg.Engines["node"])}}
+}
+
+// parseDockerfile returns the images of the FROM lines. Build stages and
+// images set by build arguments are skipped.
+func parseDockerfile(data []byte) []pin {
+	var pins []pin
+	stages := map[string]bool{}
+	for _, match := range fromPattern.FindAllSubmatch(data, -1) {
+		image := string(match[1])
+		if !stages[strings.ToLower(image)] && !strings.Contains(image, "$") && image != "scratch" {
+			pins = append(pins, pin{Image: image})
+		}
+		if len(match[2]) > 0 {
+			stages[strings.ToLower(string(match[2]))] = true
+		}
+	}
+	return pins
+}
+
+// versionFile returns a parser for files holding just a version, like
+// .python-version. Aliases such as "lts/hydrogen" have no version.
+func versionFile(runtime string) parser {
+	return func(data []byte) []pin {
+		line, _, _ := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+		version, ok := discovery.TagVersion(strings.TrimSpace(string(line)))
+		if !ok {
+			return nil
+		}
+		return []pin{{Runtime: runtime, Version: version}}
+	}
+}
+
+// parseToolVersions parses the asdf .tool-versions file, e.g. "nodejs 18.17.0".
+func parseToolVersions(data []byte) []pin {
+	var pins []pin
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		// Java versions are prefixed with the distribution, e.g. temurin-17.0.8
+		if version := firstVersion(fields[1]); version != "" {
+			pins = append(pins, pin{Runtime: fields[0], Version: version})
+		}
+	}
+	return pins
+}
+
+// parsePom returns the Java version of a Maven project. Versions like 1.8
+// are Java 8.
+func parsePom(data []byte) []pin {
+	match := pomPattern.FindSubmatch(data)
+	if match == nil || bytes.Contains(match[2], []byte("${")) {
+		return nil
+	}
+	version := strings.TrimPrefix(string(match[2]), "1.")
+	return []pin{{Runtime: "java", Version: version}}
+}
+
+func parseGemfile(data []byte) []pin {
+	match := gemfilePattern.FindSubmatch(data)
+	if match == nil {
+		return nil
+	}
+	return []pin{{Runtime: "ruby", Version: firstVersion(string(match[1]))}}
+}
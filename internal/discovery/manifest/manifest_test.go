@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+func TestManifest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Manifest Discovery Suite")
+}
+
+// writeFiles creates the files below root, creating directories as needed.
+func writeFiles(root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+}
+
+func installation(product, version, repo, file string) discovery.Installation {
+	return discovery.Installation{Product: product, Version: version, Labels: map[string]string{"repo": repo, "file": file}}
+}
+
+var _ = Describe("Manifest Discovery Suite", func() {
+	resolver := discovery.NewResolver(nil, nil)
+
+	It("should discover the pinned versions of all repositories", func() {
+		root := GinkgoT().TempDir()
+		writeFiles(root, map[string]string{
+			"api/.git/HEAD":                     "ref: refs/heads/main",
+			"api/go.mod":                        "module example.com/api\n\ngo 1.20\n\ntoolchain go1.21.5\n",
+			"api/Dockerfile":                    "FROM golang:1.20-alpine AS build\nFROM build AS test\nFROM alpine:3.18\nARG BASE\nFROM ${BASE}\n",
+			"web/.git/HEAD":                     "ref: refs/heads/main",
+			"web/package.json":                  `{"name": "web", "engines": {"node": ">=16.14.0"}}`,
+			"web/.nvmrc":                        "lts/hydrogen\n",
+			"web/node_modules/dep/package.json": `{"engines": {"node": ">=10"}}`,
+			"ml/.python-version":                "3.8.18\n",
+			"ml/.tool-versions":                 "# asdf\nnodejs 18.17.0\njava temurin-17.0.8+7\n",
+			"legacy/app/pom.xml":                "<project><properties><java.version>1.8</java.version></properties></project>",
+			"legacy/app/Gemfile":                "source 'https://rubygems.org'\nruby '~> 2.7.0'\n",
+			"legacy/app/.git":                   "gitdir: ../.git/modules/app",
+			"legacy/README.md":                  "# Legacy",
+		})
+
+		installations, err := New(root, resolver).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			installation("go", "1.20", "api", "go.mod"),
+			installation("go", "1.20", "api", "Dockerfile"),
+			installation("alpine-linux", "3.18", "api", "Dockerfile"),
+			installation("nodejs", "16.14.0", "web", "package.json"),
+			installation("python", "3.8.18", "ml", ".python-version"),
+			installation("nodejs", "18.17.0", "ml", ".tool-versions"),
+			installation("eclipse-temurin", "17.0.8", "ml", ".tool-versions"),
+			installation("eclipse-temurin", "8", "legacy/app", "pom.xml"),
+			installation("ruby", "2.7.0", "legacy/app", "Gemfile"),
+		))
+	})
+
+	It("should name a scanned repository after its directory", func() {
+		root := filepath.Join(GinkgoT().TempDir(), "billing")
+		writeFiles(root, map[string]string{
+			".git/HEAD":         "ref: refs/heads/main",
+			"go.mod":            "module billing\n\ngo 1.22.1\n",
+			"tools/lint/go.mod": "module lint\n\ngo 1.21\n",
+		})
+
+		installations, err := New(root, resolver).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			installation("go", "1.22.1", "billing", "go.mod"),
+			installation("go", "1.21", "billing", "tools/lint/go.mod"),
+		))
+	})
+})
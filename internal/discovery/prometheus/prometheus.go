@@ -0,0 +1,126 @@
+// Package prometheus discovers installed versions from the build and version
+// info series of a Prometheus server.
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+// Config holds the Prometheus discovery flags.
+type Config struct {
+	URL     string        `env:"URL" help:"URL of the Prometheus server whose version info series are queried on every refresh. Disabled if empty."`
+	Timeout time.Duration `env:"TIMEOUT" default:"30s" help:"Timeout of a query."`
+}
+
+// Rule maps the series of a query to a product. The version is the first
+// version number in the value of Label, e.g. "1.21.5" of "go1.21.5".
+type Rule struct {
+	Query   string
+	Label   string
+	Product string
+}
+
+// DefaultRules cover the version info series of common exporters. They are
+// extended by the configured rules, a configured rule with the same query
+// replaces a default.
+var DefaultRules = []Rule{
+	{Query: "go_info", Label: "version", Product: "go"},
+	{Query: "node_uname_info", Label: "release", Product: "linux"},
+	{Query: "pg_static", Label: "version", Product: "postgresql"},
+	{Query: "redis_instance_info", Label: "redis_version", Product: "redis"},
+	{Query: `mysql_version_info{version!~".*MariaDB.*"}`, Label: "version", Product: "mysql"},
+	{Query: `mysql_version_info{version=~".*MariaDB.*"}`, Label: "version", Product: "mariadb"},
+}
+
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// Source discovers versions by querying a Prometheus server. Installations
+// are labeled with the job and instance of their series.
+type Source struct {
+	api     v1.API
+	rules   []Rule
+	timeout time.Duration
+}
+
+// New returns a source for the Prometheus server at cfg.URL with the default
+// rules and the given rules.
+func New(cfg Config, rules []Rule) (*Source, error) {
+	client, err := api.NewClient(api.Config{Address: cfg.URL})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus client: %w", err)
+	}
+
+	merged := make([]Rule, 0, len(DefaultRules)+len(rules))
+	for _, rule := range DefaultRules {
+		if !slices.ContainsFunc(rules, func(r Rule) bool { return r.Query == rule.Query }) {
+			merged = append(merged, rule)
+		}
+	}
+	merged = append(merged, rules...)
+
+	return &Source{api: v1.NewAPI(client), rules: merged, timeout: cfg.Timeout}, nil
+}
+
+func (s *Source) Name() string { return "prometheus" }
+
+func (s *Source) LabelNames() []string { return []string{"instance", "job"} }
+
+// Discover runs the query of every rule. A failing query fails the discovery,
+// so that the installations of a temporarily unreachable server are kept.
+func (s *Source) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	var installations []discovery.Installation
+
+	for _, rule := range s.rules {
+		vector, err := s.query(ctx, rule.Query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %q: %w", rule.Query, err)
+		}
+		for _, sample := range vector {
+			value := string(sample.Metric[model.LabelName(rule.Label)])
+			version := versionPattern.FindString(value)
+			if version == "" {
+				slog.Debug("Skipping series without version", "query", rule.Query, "series", sample.Metric.String())
+				continue
+			}
+			installations = append(installations, discovery.Installation{
+				Product: rule.Product,
+				Version: version,
+				Labels: map[string]string{
+					"instance": string(sample.Metric[model.InstanceLabel]),
+					"job":      string(sample.Metric[model.JobLabel]),
+				},
+			})
+		}
+	}
+
+	return installations, nil
+}
+
+// query runs an instant query, which must return a vector.
+func (s *Source) query(ctx context.Context, query string) (model.Vector, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	value, warnings, err := s.api.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		slog.Warn("Prometheus query warning", "query", query, "warning", warning)
+	}
+	vector, ok := value.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %s", value.Type())
+	}
+	return vector, nil
+}
@@ -0,0 +1,96 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+func TestPrometheus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Prometheus Discovery Suite")
+}
+
+// series are the label sets returned by the stand-in server per query.
+var series = map[string][]map[string]string{
+	"go_info": {
+		{"__name__": "go_info", "job": "api", "instance": "api-0:8080", "version": "go1.21.5"},
+		{"__name__": "go_info", "job": "api", "instance": "api-1:8080", "version": "devel"},
+	},
+	"node_uname_info": {
+		{"__name__": "node_uname_info", "job": "node", "instance": "db-1:9100", "release": "5.15.0-91-generic"},
+	},
+	"pg_static": {
+		{"__name__": "pg_static", "job": "postgres", "instance": "db-1:9187", "version": "PostgreSQL 15.4 on x86_64-pc-linux-gnu", "short_version": "15.4.0"},
+	},
+	`mysql_version_info{version=~".*MariaDB.*"}`: {
+		{"__name__": "mysql_version_info", "job": "mysql", "instance": "db-2:9104", "version": "10.11.6-MariaDB-1:10.11.6+maria~ubu2204"},
+	},
+	`app_build_info{job="web"}`: {
+		{"__name__": "app_build_info", "job": "web", "instance": "web-0:3000", "node_version": "v18.19.0"},
+	},
+}
+
+// newServer returns a stand-in for the query API of a Prometheus server.
+func newServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.URL.Path).To(Equal("/api/v1/query"))
+		Expect(r.ParseForm()).To(Succeed())
+
+		result := []map[string]any{}
+		for _, metric := range series[r.Form.Get("query")] {
+			result = append(result, map[string]any{"metric": metric, "value": []any{1700000000, "1"}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		Expect(json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data":   map[string]any{"resultType": "vector", "result": result},
+		})).To(Succeed())
+	}))
+}
+
+func installation(product, version, job, instance string) discovery.Installation {
+	return discovery.Installation{Product: product, Version: version, Labels: map[string]string{"job": job, "instance": instance}}
+}
+
+var _ = Describe("Prometheus Discovery Suite", func() {
+	It("should discover the versions of the default and configured rules", func() {
+		server := newServer()
+		defer server.Close()
+
+		source, err := New(Config{URL: server.URL, Timeout: time.Second}, []Rule{
+			{Query: `app_build_info{job="web"}`, Label: "node_version", Product: "nodejs"},
+			{Query: "pg_static", Label: "short_version", Product: "postgresql"},
+		})
+		Expect(err).To(BeNil())
+
+		installations, err := source.Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			installation("go", "1.21.5", "api", "api-0:8080"),
+			installation("linux", "5.15.0", "node", "db-1:9100"),
+			installation("postgresql", "15.4.0", "postgres", "db-1:9187"),
+			installation("mariadb", "10.11.6", "mysql", "db-2:9104"),
+			installation("nodejs", "18.19.0", "web", "web-0:3000"),
+		))
+	})
+
+	It("should fail when the server is unreachable", func() {
+		server := newServer()
+		server.Close()
+
+		source, err := New(Config{URL: server.URL, Timeout: time.Second}, nil)
+		Expect(err).To(BeNil())
+
+		_, err = source.Discover(context.Background())
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,192 @@
+package discovery
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMappings map common image and package names to endoflife.date
+// products. They are overridden by the configured mappings.
+var DefaultMappings = map[string]string{
+	"alpine":          "alpine-linux",
+	"argo-cd":         "argo-cd",
+	"cert-manager":    "cert-manager",
+	"debian":          "debian",
+	"eclipse-temurin": "eclipse-temurin",
+	"elasticsearch":   "elasticsearch",
+	"go":              "go",
+	"golang":          "go",
+	"grafana":         "grafana",
+	"haproxy":         "haproxy",
+	"ingress-nginx":   "ingress-nginx",
+	"java":            "eclipse-temurin",
+	"kafka":           "apache-kafka",
+	"kibana":          "kibana",
+	"mariadb":         "mariadb",
+	"mongo":           "mongodb",
+	"mongodb":         "mongodb",
+	"mysql":           "mysql",
+	"nginx":           "nginx",
+	"openssl":         "openssl",
+	"node":            "nodejs",
+	"nodejs":          "nodejs",
+	"php":             "php",
+	"postgres":        "postgresql",
+	"postgresql":      "postgresql",
+	"prometheus":      "prometheus",
+	"python":          "python",
+	"rabbitmq":        "rabbitmq",
+	"redis":           "redis",
+	"ruby":            "ruby",
+	"tomcat":          "tomcat",
+	"traefik":         "traefik",
+	"ubuntu":          "ubuntu",
+	"valkey":          "valkey",
+}
+
+// IdentifierClient looks up the products of identifiers, it is implemented by
+// the endoflife.date client.
+type IdentifierClient interface {
+	GetIdentifiers(ctx context.Context, identifierType string) (map[string]string, error)
+}
+
+const (
+	// identifierTTL is how long the identifiers of a type are cached
+	identifierTTL = 24 * time.Hour
+	// identifierRetry is how long a failed lookup is cached
+	identifierRetry = 5 * time.Minute
+)
+
+// Resolver maps names and identifiers found by the sources to endoflife.date
+// products.
+type Resolver struct {
+	mappings   map[string]string
+	configured map[string]string
+	client     IdentifierClient
+
+	mu          sync.Mutex
+	identifiers map[string]identifierCache
+}
+
+type identifierCache struct {
+	products map[string]string // Normalized identifier to product
+	expires  time.Time
+}
+
+// NewResolver returns a resolver with the given mappings on top of the
+// defaults. Identifiers are not resolved if client is nil.
+func NewResolver(mappings map[string]string, client IdentifierClient) *Resolver {
+	merged := make(map[string]string, len(DefaultMappings)+len(mappings))
+	for name, product := range DefaultMappings {
+		merged[name] = product
+	}
+	configured := make(map[string]string, len(mappings))
+	for name, product := range mappings {
+		merged[strings.ToLower(name)] = product
+		configured[strings.ToLower(name)] = product
+	}
+
+	return &Resolver{
+		mappings:    merged,
+		configured:  configured,
+		client:      client,
+		identifiers: make(map[string]identifierCache),
+	}
+}
+
+// Name returns the product of a name, e.g. an image repository such as
+// "bitnami/postgresql". The full name is looked up before its last segment.
+func (r *Resolver) Name(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if product, ok := r.mappings[name]; ok {
+		return product, true
+	}
+	product, ok := r.mappings[path.Base(name)]
+	return product, ok
+}
+
+// Configured returns the product of a configured mapping only. It is used by
+// sources whose names have built-in products that differ from the default
+// mappings, e.g. the "postgres" engine of RDS.
+func (r *Resolver) Configured(name string) (string, bool) {
+	product, ok := r.configured[strings.ToLower(name)]
+	return product, ok
+}
+
+// Identifier returns the product of an identifier of the given type, e.g. the
+// purl "pkg:docker/library/postgres" or the cpe "cpe:/a:nginx:nginx".
+// Versions and qualifiers are ignored.
+func (r *Resolver) Identifier(ctx context.Context, identifierType, id string) (string, bool) {
+	if r.client == nil {
+		return "", false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cache, ok := r.identifiers[identifierType]
+	if !ok || time.Now().After(cache.expires) {
+		cache = identifierCache{expires: time.Now().Add(identifierTTL)}
+		products, err := r.client.GetIdentifiers(ctx, identifierType)
+		if err != nil {
+			slog.Warn("Failed to get identifiers", "type", identifierType, "error", err)
+			cache.expires = time.Now().Add(identifierRetry)
+		}
+		cache.products = make(map[string]string, len(products))
+		for identifier, product := range products {
+			cache.products[normalizeIdentifier(identifierType, identifier)] = product
+		}
+		r.identifiers[identifierType] = cache
+	}
+
+	product, ok := cache.products[normalizeIdentifier(identifierType, id)]
+	return product, ok
+}
+
+// Image returns the product and version of an image reference. The version is
+// taken from the tag, images without a version tag are not resolved.
+func (r *Resolver) Image(ctx context.Context, ref string) (product, version string, ok bool) {
+	image := ParseImage(ref)
+	version, ok = TagVersion(image.Tag)
+	if !ok {
+		return "", "", false
+	}
+
+	if product, ok := r.Name(image.Repository); ok {
+		return product, version, true
+	}
+	if product, ok := r.Identifier(ctx, "purl", image.PURL()); ok {
+		return product, version, true
+	}
+	return "", "", false
+}
+
+// normalizeIdentifier strips the version, qualifiers and subpath of purls. CPEs
+// in the 2.2 and 2.3 format are reduced to "cpe:/part:vendor:product".
+func normalizeIdentifier(identifierType, id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	switch identifierType {
+	case "purl":
+		if i := strings.IndexAny(id, "?#"); i >= 0 {
+			id = id[:i]
+		}
+		if i := strings.LastIndex(id, "@"); i > strings.LastIndex(id, "/") {
+			id = id[:i]
+		}
+	case "cpe":
+		var fields []string
+		if rest, ok := strings.CutPrefix(id, "cpe:2.3:"); ok {
+			fields = strings.Split(rest, ":")
+		} else if rest, ok := strings.CutPrefix(id, "cpe:/"); ok {
+			fields = strings.Split(rest, ":")
+		}
+		if len(fields) >= 3 {
+			id = "cpe:/" + strings.Join(fields[:3], ":")
+		}
+	}
+	return id
+}
@@ -0,0 +1,275 @@
+// Package sbom discovers the components of CycloneDX and SPDX JSON SBOMs that
+// are tracked by endoflife.date.
+package sbom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+// Config holds the SBOM discovery flags.
+type Config struct {
+	Path     string        `env:"PATH" help:"Directory of CycloneDX and SPDX JSON SBOMs, watched for added, changed and removed files. Disabled if empty."`
+	Interval time.Duration `env:"INTERVAL" default:"1m" help:"How often the SBOM directory is checked for changes, which trigger a refresh. Only read on every refresh if 0."`
+}
+
+// component is a package of an SBOM with its identifiers.
+type component struct {
+	Name    string
+	Version string
+	PURLs   []string
+	CPEs    []string
+}
+
+// document is an SBOM reduced to its subject and components.
+type document struct {
+	Subject        string
+	SubjectVersion string
+	Components     []component
+}
+
+// Source discovers the components of the SBOMs in a directory.
+type Source struct {
+	dir      string
+	resolver *discovery.Resolver
+}
+
+// New returns a source for the SBOMs in dir.
+func New(dir string, resolver *discovery.Resolver) *Source {
+	return &Source{dir: dir, resolver: resolver}
+}
+
+func (s *Source) Name() string { return "sbom" }
+
+func (s *Source) LabelNames() []string { return []string{"subject", "subject_version"} }
+
+// Discover reads all JSON files in the directory. Files that are not a valid
+// SBOM are skipped, so a single broken file does not hide the others.
+func (s *Source) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	if _, err := os.Stat(s.dir); err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var installations []discovery.Installation
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := parse(data)
+		if err != nil {
+			slog.Warn("Skipping invalid SBOM", "file", file, "error", err)
+			continue
+		}
+		if doc.Subject == "" {
+			doc.Subject = strings.TrimSuffix(filepath.Base(file), ".json")
+		}
+
+		for _, c := range doc.Components {
+			product, ok := s.resolve(ctx, c)
+			if !ok || c.Version == "" {
+				continue
+			}
+			installations = append(installations, discovery.Installation{
+				Product: product,
+				Version: c.Version,
+				Labels:  map[string]string{"subject": doc.Subject, "subject_version": doc.SubjectVersion},
+			})
+		}
+	}
+
+	return installations, nil
+}
+
+// Watch calls changed whenever a JSON file in dir was added, modified or
+// removed, checking every interval until ctx is done. Polling also works for
+// bind mounts and ConfigMap volumes, whose updates are not always notified.
+func Watch(ctx context.Context, dir string, interval time.Duration, changed func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := fingerprint(dir)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if current := fingerprint(dir); current != last {
+			slog.Info("SBOM directory changed", "path", dir)
+			last = current
+			changed()
+		}
+	}
+}
+
+// fingerprint returns the names, sizes and modification times of the JSON
+// files in dir.
+func fingerprint(dir string) string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d\n", filepath.Base(file), info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
+
+// resolve returns the product of the first purl or cpe of the component known
+// to endoflife.date.
+func (s *Source) resolve(ctx context.Context, c component) (string, bool) {
+	for _, purl := range c.PURLs {
+		if product, ok := s.resolver.Identifier(ctx, "purl", purl); ok {
+			return product, true
+		}
+	}
+	for _, cpe := range c.CPEs {
+		if product, ok := s.resolver.Identifier(ctx, "cpe", cpe); ok {
+			return product, true
+		}
+	}
+	return "", false
+}
+
+// parse detects the format of an SBOM and parses it.
+func parse(data []byte) (document, error) {
+	var header struct {
+		BOMFormat   string `json:"bomFormat"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return document{}, err
+	}
+
+	switch {
+	case header.BOMFormat == "CycloneDX":
+		return parseCycloneDX(data)
+	case strings.HasPrefix(header.SPDXVersion, "SPDX-"):
+		return parseSPDX(data)
+	default:
+		return document{}, fmt.Errorf("neither a CycloneDX nor an SPDX document")
+	}
+}
+
+type cycloneDXComponent struct {
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	PURL       string               `json:"purl"`
+	CPE        string               `json:"cpe"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+func parseCycloneDX(data []byte) (document, error) {
+	var bom struct {
+		Metadata struct {
+			Component cycloneDXComponent `json:"component"`
+		} `json:"metadata"`
+		Components []cycloneDXComponent `json:"components"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		return document{}, err
+	}
+
+	doc := document{
+		Subject:        bom.Metadata.Component.Name,
+		SubjectVersion: bom.Metadata.Component.Version,
+	}
+
+	// Components may be nested, e.g. the packages of an OS component
+	var walk func([]cycloneDXComponent)
+	walk = func(components []cycloneDXComponent) {
+		for _, c := range components {
+			doc.Components = append(doc.Components, component{
+				Name:    c.Name,
+				Version: c.Version,
+				PURLs:   nonEmpty(c.PURL),
+				CPEs:    nonEmpty(c.CPE),
+			})
+			walk(c.Components)
+		}
+	}
+	walk(bom.Components)
+
+	return doc, nil
+}
+
+func parseSPDX(data []byte) (document, error) {
+	var spdx struct {
+		Name              string   `json:"name"`
+		DocumentDescribes []string `json:"documentDescribes"`
+		Relationships     []struct {
+			SPDXElementID      string `json:"spdxElementId"`
+			RelationshipType   string `json:"relationshipType"`
+			RelatedSPDXElement string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+		Packages []struct {
+			SPDXID       string `json:"SPDXID"`
+			Name         string `json:"name"`
+			VersionInfo  string `json:"versionInfo"`
+			ExternalRefs []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &spdx); err != nil {
+		return document{}, err
+	}
+
+	// SPDX 2.3 replaced documentDescribes with DESCRIBES relationships
+	described := spdx.DocumentDescribes
+	for _, rel := range spdx.Relationships {
+		if rel.SPDXElementID == "SPDXRef-DOCUMENT" && rel.RelationshipType == "DESCRIBES" {
+			described = append(described, rel.RelatedSPDXElement)
+		}
+	}
+
+	doc := document{Subject: spdx.Name}
+	for _, p := range spdx.Packages {
+		// The described package is the subject, not a component
+		if slices.Contains(described, p.SPDXID) {
+			doc.Subject = p.Name
+			doc.SubjectVersion = p.VersionInfo
+			continue
+		}
+
+		c := component{Name: p.Name, Version: p.VersionInfo}
+		for _, ref := range p.ExternalRefs {
+			switch ref.ReferenceType {
+			case "purl":
+				c.PURLs = append(c.PURLs, ref.ReferenceLocator)
+			case "cpe22Type", "cpe23Type":
+				c.CPEs = append(c.CPEs, ref.ReferenceLocator)
+			}
+		}
+		doc.Components = append(doc.Components, c)
+	}
+
+	return doc, nil
+}
+
+func nonEmpty(values ...string) []string {
+	return slices.DeleteFunc(values, func(v string) bool { return v == "" })
+}
@@ -0,0 +1,105 @@
+package sbom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+func TestSBOM(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SBOM Discovery Suite")
+}
+
+// fakeIdentifiers returns fixed identifiers by type.
+type fakeIdentifiers map[string]map[string]string
+
+func (f fakeIdentifiers) GetIdentifiers(_ context.Context, identifierType string) (map[string]string, error) {
+	return f[identifierType], nil
+}
+
+const cycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {"component": {"type": "application", "name": "checkout", "version": "3.1.0"}},
+  "components": [
+    {"type": "library", "name": "django", "version": "4.1.7", "purl": "pkg:pypi/django@4.1.7"},
+    {"type": "library", "name": "requests", "version": "2.31.0", "purl": "pkg:pypi/requests@2.31.0"},
+    {"type": "operating-system", "name": "debian", "version": "12.4", "components": [
+      {"type": "library", "name": "openssl", "version": "3.0.11", "cpe": "cpe:2.3:a:openssl:openssl:3.0.11:*:*:*:*:*:*:*"}
+    ]}
+  ]
+}`
+
+const spdx = `{
+  "spdxVersion": "SPDX-2.3",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "sbom-payments",
+  "packages": [
+    {"SPDXID": "SPDXRef-payments", "name": "payments", "versionInfo": "1.4.2"},
+    {"SPDXID": "SPDXRef-node", "name": "node", "versionInfo": "18.19.0", "externalRefs": [
+      {"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:generic/node@18.19.0"}
+    ]},
+    {"SPDXID": "SPDXRef-nginx", "name": "nginx", "versionInfo": "1.24.0", "externalRefs": [
+      {"referenceCategory": "SECURITY", "referenceType": "cpe23Type", "referenceLocator": "cpe:2.3:a:f5:nginx:1.24.0:*:*:*:*:*:*:*"}
+    ]}
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-payments"}
+  ]
+}`
+
+var _ = Describe("SBOM Discovery Suite", func() {
+	resolver := discovery.NewResolver(nil, fakeIdentifiers{
+		"purl": {"pkg:pypi/django": "django", "pkg:generic/node": "nodejs"},
+		"cpe":  {"cpe:/a:openssl:openssl": "openssl", "cpe:/a:f5:nginx": "nginx"},
+	})
+
+	It("should discover the components of CycloneDX and SPDX documents", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "checkout.cdx.json"), []byte(cycloneDX), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "payments.spdx.json"), []byte(spdx), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"bomFormat":`), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte(`# SBOMs`), 0o644)).To(Succeed())
+
+		installations, err := New(dir, resolver).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		checkout := map[string]string{"subject": "checkout", "subject_version": "3.1.0"}
+		payments := map[string]string{"subject": "payments", "subject_version": "1.4.2"}
+		Expect(installations).To(ConsistOf(
+			discovery.Installation{Product: "django", Version: "4.1.7", Labels: checkout},
+			discovery.Installation{Product: "openssl", Version: "3.0.11", Labels: checkout},
+			discovery.Installation{Product: "nodejs", Version: "18.19.0", Labels: payments},
+			discovery.Installation{Product: "nginx", Version: "1.24.0", Labels: payments},
+		))
+	})
+
+	It("should fail when the directory does not exist", func() {
+		_, err := New(filepath.Join(GinkgoT().TempDir(), "missing"), resolver).Discover(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should notify when an SBOM is added or removed", func() {
+		dir := GinkgoT().TempDir()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		changed := make(chan struct{}, 10)
+		go Watch(ctx, dir, 10*time.Millisecond, func() { changed <- struct{}{} })
+		Consistently(changed, 50*time.Millisecond).ShouldNot(Receive())
+
+		Expect(os.WriteFile(filepath.Join(dir, "checkout.json"), []byte(cycloneDX), 0o644)).To(Succeed())
+		Eventually(changed).Should(Receive())
+		Consistently(changed, 50*time.Millisecond).ShouldNot(Receive())
+
+		Expect(os.Remove(filepath.Join(dir, "checkout.json"))).To(Succeed())
+		Eventually(changed).Should(Receive())
+	})
+})
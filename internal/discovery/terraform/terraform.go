@@ -0,0 +1,237 @@
+// Package terraform discovers the versions of managed databases and clusters
+// in Terraform state files.
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+// Config holds the Terraform discovery flags.
+type Config struct {
+	Path string `env:"PATH" help:"Terraform state file, or directory searched for *.tfstate files, read on every refresh. Disabled if empty."`
+}
+
+// engineProducts map the engine attribute of RDS and ElastiCache resources to
+// their product. Configured mappings take precedence, e.g. for valkey.
+var engineProducts = map[string]string{
+	"aurora-mysql":      "amazon-aurora-mysql",
+	"aurora-postgresql": "amazon-aurora-postgresql",
+	"mariadb":           "amazon-rds-mariadb",
+	"mysql":             "amazon-rds-mysql",
+	"postgres":          "amazon-rds-postgresql",
+	"redis":             "amazon-elasticache-redis",
+}
+
+// attributes are the attributes of a resource instance.
+type attributes map[string]any
+
+// first returns the first non-empty string attribute of names.
+func (a attributes) first(names ...string) string {
+	for _, name := range names {
+		if value, ok := a[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// resourceType is a tracked resource type. Its product is fixed or, for
+// engine resources, looked up by the engine attribute.
+type resourceType struct {
+	product  string
+	engine   bool
+	versions []string // Version attributes, the first set one is used
+}
+
+// resourceTypes are the tracked resource types.
+var resourceTypes = map[string]resourceType{
+	"aws_db_instance":                   {engine: true, versions: []string{"engine_version_actual", "engine_version"}},
+	"aws_rds_cluster":                   {engine: true, versions: []string{"engine_version_actual", "engine_version"}},
+	"aws_elasticache_cluster":           {engine: true, versions: []string{"engine_version_actual", "engine_version"}},
+	"aws_elasticache_replication_group": {engine: true, versions: []string{"engine_version_actual", "engine_version"}},
+	"aws_eks_cluster":                   {product: "amazon-eks", versions: []string{"version"}},
+	"azurerm_kubernetes_cluster":        {product: "azure-kubernetes-service", versions: []string{"current_kubernetes_version", "kubernetes_version"}},
+	"google_container_cluster":          {product: "google-kubernetes-engine", versions: []string{"master_version", "min_master_version"}},
+}
+
+var (
+	versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+	// auroraMySQLPattern matches the Aurora version of Aurora MySQL engine
+	// versions like "8.0.mysql_aurora.3.05.2", its release cycles are
+	// versioned by it instead of the MySQL version.
+	auroraMySQLPattern = regexp.MustCompile(`mysql_aurora\.(\d+(\.\d+)*)`)
+)
+
+// state is a Terraform state file in the version 4 format.
+type state struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   any        `json:"index_key"`
+			Attributes attributes `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// Source discovers the versions of the managed resources in Terraform state
+// files. Installations are labeled with the resource address and the state
+// file it was read from.
+type Source struct {
+	path     string
+	resolver *discovery.Resolver
+}
+
+// New returns a source for the state file at path, or the state files below
+// the directory at path.
+func New(path string, resolver *discovery.Resolver) *Source {
+	return &Source{path: path, resolver: resolver}
+}
+
+func (s *Source) Name() string { return "terraform" }
+
+func (s *Source) LabelNames() []string { return []string{"resource", "state"} }
+
+// Discover reads all state files. Files that are not a valid version 4 state
+// are skipped, so a single broken file does not hide the others.
+func (s *Source) Discover(ctx context.Context) ([]discovery.Installation, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var installations []discovery.Installation
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		found, err := s.read(file)
+		if err != nil {
+			slog.Warn("Skipping invalid terraform state", "file", file, "error", err)
+			continue
+		}
+		installations = append(installations, found...)
+	}
+
+	return installations, nil
+}
+
+// files returns the state file at path, or the *.tfstate files below it.
+// Hidden directories such as .terraform are skipped.
+func (s *Source) files() ([]string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{s.path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(s.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != s.path && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".tfstate" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// read returns the installations of the tracked resources of a state file.
+func (s *Source) read(file string) ([]discovery.Installation, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	if st.Version != 4 {
+		return nil, fmt.Errorf("unsupported state version %d", st.Version)
+	}
+
+	name := filepath.Base(file)
+	if rel, err := filepath.Rel(s.path, file); err == nil && rel != "." {
+		name = filepath.ToSlash(rel)
+	}
+
+	var installations []discovery.Installation
+	for _, resource := range st.Resources {
+		rt, ok := resourceTypes[resource.Type]
+		if resource.Mode != "managed" || !ok {
+			continue
+		}
+		for _, instance := range resource.Instances {
+			addr := address(resource.Module, resource.Type, resource.Name, instance.IndexKey)
+			product := rt.product
+			if rt.engine {
+				product = s.engineProduct(instance.Attributes.first("engine"))
+			}
+			version := engineVersion(instance.Attributes.first(rt.versions...))
+			if product == "" || version == "" {
+				slog.Debug("Skipping resource of unknown engine or version, the engine can be mapped in the configuration",
+					"state", name, "resource", addr, "engine", instance.Attributes.first("engine"))
+				continue
+			}
+			installations = append(installations, discovery.Installation{
+				Product: product,
+				Version: version,
+				Labels:  map[string]string{"resource": addr, "state": name},
+			})
+		}
+	}
+
+	return installations, nil
+}
+
+// engineProduct returns the product of an engine, empty if it is unknown.
+func (s *Source) engineProduct(engine string) string {
+	if product, ok := s.resolver.Configured(engine); ok {
+		return product
+	}
+	return engineProducts[engine]
+}
+
+// engineVersion returns the version number of a version attribute.
+func engineVersion(value string) string {
+	if match := auroraMySQLPattern.FindStringSubmatch(value); match != nil {
+		return match[1]
+	}
+	return versionPattern.FindString(value)
+}
+
+// address returns the address of a resource instance as printed by Terraform,
+// e.g. module.db.aws_db_instance.main["eu"].
+func address(module, resourceType, name string, indexKey any) string {
+	addr := resourceType + "." + name
+	if module != "" {
+		addr = module + "." + addr
+	}
+	switch key := indexKey.(type) {
+	case string:
+		addr += fmt.Sprintf("[%q]", key)
+	case float64:
+		addr += fmt.Sprintf("[%d]", int(key))
+	}
+	return addr
+}
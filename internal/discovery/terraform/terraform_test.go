@@ -0,0 +1,167 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+)
+
+func TestTerraform(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Terraform Discovery Suite")
+}
+
+// writeFiles creates the files below root, creating directories as needed.
+func writeFiles(root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+}
+
+func installation(product, version, resource, state string) discovery.Installation {
+	return discovery.Installation{Product: product, Version: version, Labels: map[string]string{"resource": resource, "state": state}}
+}
+
+const awsState = `{
+  "version": 4,
+  "terraform_version": "1.6.5",
+  "resources": [
+    {
+      "module": "module.db",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "main",
+      "instances": [
+        {"index_key": "eu", "attributes": {"engine": "postgres", "engine_version": "15", "engine_version_actual": "15.4"}},
+        {"index_key": "us", "attributes": {"engine": "mysql", "engine_version": "8.0.35"}}
+      ]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "legacy",
+      "instances": [{"attributes": {"engine": "oracle-ee", "engine_version": "19.0.0.0.ru-2023-10.rur-2023-10.r1"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_elasticache_replication_group",
+      "name": "cache",
+      "instances": [{"attributes": {"engine": "redis", "engine_version": "7.0", "engine_version_actual": "7.0.7"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_eks_cluster",
+      "name": "main",
+      "instances": [{"index_key": 0, "attributes": {"name": "prod", "version": "1.27"}}]
+    },
+    {
+      "mode": "data",
+      "type": "aws_eks_cluster",
+      "name": "existing",
+      "instances": [{"attributes": {"name": "staging", "version": "1.24"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "assets",
+      "instances": [{"attributes": {"bucket": "assets"}}]
+    }
+  ]
+}`
+
+const azureState = `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "azurerm_kubernetes_cluster",
+      "name": "aks",
+      "instances": [{"attributes": {"kubernetes_version": "1.28", "current_kubernetes_version": "1.28.3"}}]
+    }
+  ]
+}`
+
+var _ = Describe("Terraform Discovery Suite", func() {
+	It("should discover the versions of the resources of all state files", func() {
+		root := GinkgoT().TempDir()
+		writeFiles(root, map[string]string{
+			"aws/terraform.tfstate":              awsState,
+			"azure/prod.tfstate":                 azureState,
+			"azure/.terraform/terraform.tfstate": `{"version": 3, "backend": {"type": "azurerm"}}`,
+			"broken.tfstate":                     `{"version": 3}`,
+			"aws/main.tf":                        `resource "aws_eks_cluster" "main" {}`,
+		})
+
+		installations, err := New(root, discovery.NewResolver(nil, nil)).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			installation("amazon-rds-postgresql", "15.4", `module.db.aws_db_instance.main["eu"]`, "aws/terraform.tfstate"),
+			installation("amazon-rds-mysql", "8.0.35", `module.db.aws_db_instance.main["us"]`, "aws/terraform.tfstate"),
+			installation("amazon-elasticache-redis", "7.0.7", "aws_elasticache_replication_group.cache", "aws/terraform.tfstate"),
+			installation("amazon-eks", "1.27", "aws_eks_cluster.main[0]", "aws/terraform.tfstate"),
+			installation("azure-kubernetes-service", "1.28.3", "azurerm_kubernetes_cluster.aks", "azure/prod.tfstate"),
+		))
+	})
+
+	It("should map engines with the configured mappings first", func() {
+		root := GinkgoT().TempDir()
+		writeFiles(root, map[string]string{"terraform.tfstate": `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_rds_cluster",
+      "name": "main",
+      "instances": [{"attributes": {"engine": "aurora-mysql", "engine_version": "8.0.mysql_aurora.3.05.2"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "main",
+      "instances": [{"attributes": {"engine": "postgres", "engine_version": "15.4"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_elasticache_replication_group",
+      "name": "cache",
+      "instances": [{"attributes": {"engine": "valkey", "engine_version": "7.2"}}]
+    }
+  ]
+}`})
+		resolver := discovery.NewResolver(map[string]string{"postgres": "postgresql", "valkey": "valkey"}, nil)
+
+		installations, err := New(root, resolver).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			installation("amazon-aurora-mysql", "3.05.2", "aws_rds_cluster.main", "terraform.tfstate"),
+			installation("postgresql", "15.4", "aws_db_instance.main", "terraform.tfstate"),
+			installation("valkey", "7.2", "aws_elasticache_replication_group.cache", "terraform.tfstate"),
+		))
+	})
+
+	It("should read a single state file", func() {
+		root := GinkgoT().TempDir()
+		writeFiles(root, map[string]string{"terraform.tfstate": azureState})
+
+		installations, err := New(filepath.Join(root, "terraform.tfstate"), discovery.NewResolver(nil, nil)).Discover(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(installations).To(ConsistOf(
+			installation("azure-kubernetes-service", "1.28.3", "azurerm_kubernetes_cluster.aks", "terraform.tfstate"),
+		))
+	})
+
+	It("should fail when the path does not exist", func() {
+		_, err := New(filepath.Join(GinkgoT().TempDir(), "missing"), discovery.NewResolver(nil, nil)).Discover(context.Background())
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,172 @@
+// Package history records changes of the EOL, EOAS and EOES dates of release
+// cycles as observed between refreshes.
+package history
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/storage"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+const (
+	// historyFile is the file name of the history in the storage directory.
+	historyFile = "history.json"
+	// maxChanges is the number of changes kept, the oldest are dropped.
+	maxChanges = 10000
+)
+
+// DateType is the lifecycle date that changed.
+type DateType string
+
+const (
+	DateEOL  DateType = "eol"
+	DateEOAS DateType = "eoas"
+	DateEOES DateType = "eoes"
+)
+
+// DateTypes lists all date types.
+var DateTypes = []DateType{DateEOL, DateEOAS, DateEOES}
+
+// Change is an observed change of a lifecycle date. Zero times are unset
+// dates, EOL dates unknown to endoflife.date are endoflife.UnknownDate.
+type Change struct {
+	Product      string    `json:"product"`
+	ReleaseCycle string    `json:"release_cycle"`
+	Date         DateType  `json:"date"`
+	Old          time.Time `json:"old"`
+	New          time.Time `json:"new"`
+	ObservedAt   time.Time `json:"observed_at"`
+}
+
+// Diff returns the date changes between the old and new release cycles of a
+// product. New release cycles have no history yet and are skipped.
+func Diff(product string, old, new []endoflife.ReleaseDetails, now time.Time) []Change {
+	var changes []Change
+
+	for _, rel := range new {
+		idx := slices.IndexFunc(old, func(o endoflife.ReleaseDetails) bool {
+			return o.ReleaseCycleName == rel.ReleaseCycleName
+		})
+		if idx < 0 {
+			continue
+		}
+		prev := old[idx]
+
+		for _, d := range DateTypes {
+			oldDate, newDate := date(prev, d), date(rel, d)
+			if oldDate.Equal(newDate) {
+				continue
+			}
+			changes = append(changes, Change{
+				Product:      product,
+				ReleaseCycle: rel.ReleaseCycleName,
+				Date:         d,
+				Old:          oldDate,
+				New:          newDate,
+				ObservedAt:   now,
+			})
+		}
+	}
+
+	return changes
+}
+
+func date(rel endoflife.ReleaseDetails, d DateType) time.Time {
+	switch d {
+	case DateEOAS:
+		return rel.EOASFrom
+	case DateEOES:
+		return rel.EOESFrom
+	}
+	return rel.EOLFrom
+}
+
+// History holds the last maxChanges observed date changes, persisted to a
+// store if set. It is safe for concurrent use.
+type History struct {
+	store *storage.Store
+
+	mu      sync.RWMutex
+	changes []Change
+}
+
+// New returns an empty in-memory history.
+func New() *History {
+	return &History{}
+}
+
+// Load returns the history persisted in store. Added changes are saved to it.
+func Load(store *storage.Store) (*History, error) {
+	h := &History{store: store}
+	if _, err := store.Load(historyFile, &h.changes); err != nil {
+		return nil, err
+	}
+	h.compact()
+	return h, nil
+}
+
+// Add records changes and saves the history if it has a store. The history is
+// written as a whole, so it must not be called while holding other locks.
+func (h *History) Add(changes ...Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.changes = append(h.changes, changes...)
+	h.compact()
+	if h.store == nil {
+		return nil
+	}
+	return h.store.Save(historyFile, h.changes)
+}
+
+// compact drops the oldest changes beyond maxChanges.
+func (h *History) compact() {
+	if len(h.changes) > maxChanges {
+		h.changes = slices.Clone(h.changes[len(h.changes)-maxChanges:])
+	}
+}
+
+// Changes returns the recorded changes, newest first.
+func (h *History) Changes() []Change {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	changes := slices.Clone(h.changes)
+	slices.Reverse(changes)
+	return changes
+}
+
+// Cycle summarizes the history of a date of a release cycle.
+type Cycle struct {
+	Count int
+	// Previous is the value before the most recent change
+	Previous time.Time
+}
+
+// Cycles returns the summary per product, release cycle and date type.
+func (h *History) Cycles() map[string]map[string]map[DateType]Cycle {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	cycles := make(map[string]map[string]map[DateType]Cycle)
+	for _, c := range h.changes {
+		if cycles[c.Product] == nil {
+			cycles[c.Product] = make(map[string]map[DateType]Cycle)
+		}
+		if cycles[c.Product][c.ReleaseCycle] == nil {
+			cycles[c.Product][c.ReleaseCycle] = make(map[DateType]Cycle)
+		}
+		cycle := cycles[c.Product][c.ReleaseCycle][c.Date]
+		cycle.Count++
+		cycle.Previous = c.Old
+		cycles[c.Product][c.ReleaseCycle][c.Date] = cycle
+	}
+	return cycles
+}
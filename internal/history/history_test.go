@@ -0,0 +1,83 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/storage"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+func TestHistory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "History Suite")
+}
+
+var _ = Describe("History Suite", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	eol := time.Date(2027, 8, 31, 0, 0, 0, 0, time.UTC)
+	extended := time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	old := []endoflife.ReleaseDetails{
+		{ReleaseCycleName: "7.0", EOLFrom: eol},
+		{ReleaseCycleName: "6.0", EOLFrom: endoflife.UnknownDate},
+	}
+
+	It("should diff EOL, EOAS and EOES dates", func() {
+		updated := []endoflife.ReleaseDetails{
+			{ReleaseCycleName: "8.0", EOLFrom: endoflife.UnknownDate},
+			{ReleaseCycleName: "7.0", EOLFrom: extended, EOESFrom: extended},
+			{ReleaseCycleName: "6.0", EOLFrom: eol, EOASFrom: now},
+		}
+
+		Expect(Diff("mongo", old, updated, now)).To(Equal([]Change{
+			{Product: "mongo", ReleaseCycle: "7.0", Date: DateEOL, Old: eol, New: extended, ObservedAt: now},
+			{Product: "mongo", ReleaseCycle: "7.0", Date: DateEOES, New: extended, ObservedAt: now},
+			{Product: "mongo", ReleaseCycle: "6.0", Date: DateEOL, Old: endoflife.UnknownDate, New: eol, ObservedAt: now},
+			{Product: "mongo", ReleaseCycle: "6.0", Date: DateEOAS, New: now, ObservedAt: now},
+		}))
+		Expect(Diff("mongo", old, old, now)).To(BeEmpty())
+	})
+
+	It("should summarize the changes per release cycle", func() {
+		h := New()
+		Expect(h.Add(
+			Change{Product: "mongo", ReleaseCycle: "7.0", Date: DateEOL, Old: eol, New: extended},
+			Change{Product: "mongo", ReleaseCycle: "7.0", Date: DateEOL, Old: extended, New: now},
+		)).To(Succeed())
+
+		cycle := h.Cycles()["mongo"]["7.0"][DateEOL]
+		Expect(cycle.Count).To(Equal(2))
+		Expect(cycle.Previous).To(Equal(extended))
+		Expect(h.Changes()[0].New).To(Equal(now))
+	})
+
+	It("should drop the oldest changes beyond its size", func() {
+		h := New()
+		for i := range maxChanges + 2 {
+			Expect(h.Add(Change{Product: "mongo", ReleaseCycle: "7.0", Date: DateEOL, ObservedAt: now.Add(time.Duration(i) * time.Minute)})).To(Succeed())
+		}
+
+		changes := h.Changes()
+		Expect(changes).To(HaveLen(maxChanges))
+		Expect(changes[0].ObservedAt).To(Equal(now.Add((maxChanges + 1) * time.Minute)))
+		Expect(changes[maxChanges-1].ObservedAt).To(Equal(now.Add(2 * time.Minute)))
+	})
+
+	It("should persist the history", func() {
+		store, err := storage.New(GinkgoT().TempDir())
+		Expect(err).To(BeNil())
+
+		h, err := Load(store)
+		Expect(err).To(BeNil())
+		Expect(h.Changes()).To(BeEmpty())
+		Expect(h.Add(Change{Product: "mongo", ReleaseCycle: "7.0", Date: DateEOL, Old: eol, New: extended, ObservedAt: now})).To(Succeed())
+
+		loaded, err := Load(store)
+		Expect(err).To(BeNil())
+		Expect(loaded.Changes()).To(HaveLen(1))
+		Expect(loaded.Changes()[0].New.Equal(extended)).To(BeTrue())
+	})
+})
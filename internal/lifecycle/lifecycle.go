@@ -0,0 +1,139 @@
+// Package lifecycle derives support phases and installed-version status from
+// the release cycle details returned by the endoflife.date API.
+package lifecycle
+
+import (
+	"cmp"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+// Phase is the support phase of a release cycle.
+type Phase string
+
+const (
+	PhaseActive   Phase = "active"   // Active support, receives bug fixes and features
+	PhaseSecurity Phase = "security" // Active support ended, only security fixes
+	PhaseEOL      Phase = "eol"      // No support at all
+)
+
+// InstalledStatus describes how an installed version relates to the latest
+// version of its release cycle.
+type InstalledStatus string
+
+const (
+	StatusCurrent  InstalledStatus = "current"
+	StatusOutdated InstalledStatus = "outdated"
+	StatusUnknown  InstalledStatus = "unknown" // No tracked release cycle matches the version
+)
+
+// PhaseAt returns the support phase of the release cycle at t.
+func PhaseAt(rel endoflife.ReleaseDetails, t time.Time) Phase {
+	if rel.IsEol || !rel.EOLFrom.After(t) {
+		return PhaseEOL
+	}
+	if rel.IsEoas || (!rel.EOASFrom.IsZero() && !rel.EOASFrom.After(t)) {
+		return PhaseSecurity
+	}
+	return PhaseActive
+}
+
+// HasEOLDate reports whether the end-of-life date of the release cycle is known.
+func HasEOLDate(rel endoflife.ReleaseDetails) bool {
+	return !rel.EOLFrom.Equal(endoflife.UnknownDate)
+}
+
+// DaysToEOL returns the number of whole days from now until the end-of-life
+// date, negative if it is in the past. ok is false if the date is unknown.
+func DaysToEOL(rel endoflife.ReleaseDetails, now time.Time) (days int, ok bool) {
+	if !HasEOLDate(rel) {
+		return 0, false
+	}
+	return int(math.Floor(rel.EOLFrom.Sub(now).Hours() / 24)), true
+}
+
+// MatchRelease returns the release cycle the given version belongs to. A
+// version matches a cycle if it equals the cycle name or starts with it
+// followed by a separator, the longest matching cycle name wins.
+func MatchRelease(version string, releases []endoflife.ReleaseDetails) (endoflife.ReleaseDetails, bool) {
+	version = normalizeVersion(version)
+
+	var match endoflife.ReleaseDetails
+	found := false
+	for _, rel := range releases {
+		cycle := normalizeVersion(rel.ReleaseCycleName)
+		if cycle == "" || !strings.HasPrefix(version, cycle) {
+			continue
+		}
+		if len(version) > len(cycle) && !strings.ContainsRune(".-+_", rune(version[len(cycle)])) {
+			continue
+		}
+		if !found || len(cycle) > len(normalizeVersion(match.ReleaseCycleName)) {
+			match = rel
+			found = true
+		}
+	}
+
+	return match, found
+}
+
+// GetInstalledStatus compares an installed version with the latest version of
+// its release cycle.
+func GetInstalledStatus(installed string, rel endoflife.ReleaseDetails) InstalledStatus {
+	if rel.LatestVersion == "" || rel.LatestVersion == "N/A" {
+		return StatusUnknown
+	}
+	if CompareVersions(installed, rel.LatestVersion) < 0 {
+		return StatusOutdated
+	}
+	return StatusCurrent
+}
+
+// CompareVersions compares two dotted version strings numerically segment by
+// segment and returns -1, 0 or +1. Non-numeric segments are compared as strings.
+func CompareVersions(a, b string) int {
+	as := splitVersion(normalizeVersion(a))
+	bs := splitVersion(normalizeVersion(b))
+
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		// A missing segment counts as zero, so 1.9 equals 1.9.0
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xi, xErr := strconv.Atoi(x)
+		yi, yErr := strconv.Atoi(y)
+		switch {
+		case xErr == nil && yErr == nil:
+			if xi != yi {
+				return cmp.Compare(xi, yi)
+			}
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+
+	return 0
+}
+
+func normalizeVersion(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if len(v) > 1 && v[0] == 'v' && v[1] >= '0' && v[1] <= '9' {
+		v = v[1:]
+	}
+	return v
+}
+
+func splitVersion(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == '.' || r == '-' || r == '+' || r == '_'
+	})
+}
@@ -0,0 +1,107 @@
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+func TestLifecycle(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Lifecycle Suite")
+}
+
+var _ = Describe("Lifecycle Suite", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	Context("When computing the phase", func() {
+		It("should be active before the end of active support", func() {
+			rel := endoflife.ReleaseDetails{
+				EOASFrom: now.AddDate(0, 6, 0),
+				EOLFrom:  now.AddDate(1, 0, 0),
+			}
+			Expect(PhaseAt(rel, now)).To(Equal(PhaseActive))
+		})
+
+		It("should be security after the end of active support", func() {
+			rel := endoflife.ReleaseDetails{
+				EOASFrom: now.AddDate(0, -1, 0),
+				EOLFrom:  now.AddDate(1, 0, 0),
+			}
+			Expect(PhaseAt(rel, now)).To(Equal(PhaseSecurity))
+		})
+
+		It("should be eol when the EOL date is reached at the given time", func() {
+			rel := endoflife.ReleaseDetails{
+				EOLFrom: now.AddDate(0, 2, 0),
+			}
+			Expect(PhaseAt(rel, now)).To(Equal(PhaseActive))
+			Expect(PhaseAt(rel, now.AddDate(0, 3, 0))).To(Equal(PhaseEOL))
+		})
+
+		It("should be eol when the API flags the cycle as EOL", func() {
+			rel := endoflife.ReleaseDetails{
+				IsEol:   true,
+				EOLFrom: endoflife.UnknownDate,
+			}
+			Expect(PhaseAt(rel, now)).To(Equal(PhaseEOL))
+		})
+	})
+
+	Context("When computing days to EOL", func() {
+		It("should return negative days for past dates", func() {
+			days, ok := DaysToEOL(endoflife.ReleaseDetails{EOLFrom: now.AddDate(0, 0, -10)}, now)
+			Expect(ok).To(BeTrue())
+			Expect(days).To(Equal(-10))
+		})
+
+		It("should not return days for unknown dates", func() {
+			_, ok := DaysToEOL(endoflife.ReleaseDetails{EOLFrom: endoflife.UnknownDate}, now)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("When matching installed versions", func() {
+		releases := []endoflife.ReleaseDetails{
+			{ReleaseCycleName: "1", LatestVersion: "1.9.0"},
+			{ReleaseCycleName: "1.2", LatestVersion: "1.2.10"},
+			{ReleaseCycleName: "8.0", LatestVersion: "8.0.4"},
+		}
+
+		It("should match the longest release cycle prefix", func() {
+			rel, ok := MatchRelease("1.2.3", releases)
+			Expect(ok).To(BeTrue())
+			Expect(rel.ReleaseCycleName).To(Equal("1.2"))
+		})
+
+		It("should not match partial segments", func() {
+			rel, ok := MatchRelease("8.01", releases)
+			Expect(ok).To(BeFalse())
+			Expect(rel.ReleaseCycleName).To(BeEmpty())
+		})
+
+		It("should strip a leading v", func() {
+			rel, ok := MatchRelease("v8.0.1", releases)
+			Expect(ok).To(BeTrue())
+			Expect(rel.ReleaseCycleName).To(Equal("8.0"))
+		})
+
+		It("should detect outdated versions", func() {
+			Expect(GetInstalledStatus("1.2.3", releases[1])).To(Equal(StatusOutdated))
+			Expect(GetInstalledStatus("1.2.10", releases[1])).To(Equal(StatusCurrent))
+			Expect(GetInstalledStatus("1.2.3", endoflife.ReleaseDetails{LatestVersion: "N/A"})).To(Equal(StatusUnknown))
+		})
+	})
+
+	Context("When comparing versions", func() {
+		It("should compare numerically", func() {
+			Expect(CompareVersions("1.10.0", "1.9.0")).To(Equal(1))
+			Expect(CompareVersions("1.9", "1.9.0")).To(Equal(0))
+			Expect(CompareVersions("1.9", "1.9.1")).To(Equal(-1))
+			Expect(CompareVersions("v2.0.0", "2.0.0")).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+const dateLayout = "2006-01-02"
+
+// EventType is the kind of lifecycle event.
+type EventType string
+
+const (
+	EventEOLSoon          EventType = "eol_soon"           // EOL within a threshold
+	EventEOLReached       EventType = "eol_reached"        // Release cycle reached EOL
+	EventNewLatestVersion EventType = "new_latest_version" // New latest version of a release cycle
+)
+
+// EventTypes lists all event types.
+var EventTypes = []EventType{EventEOLSoon, EventEOLReached, EventNewLatestVersion}
+
+// Event is a lifecycle event of a release cycle. It is the data of the payload
+// templates.
+type Event struct {
+	Type            EventType         `json:"type"`
+	Product         string            `json:"product"`
+	ReleaseCycle    string            `json:"release_cycle"`
+	Message         string            `json:"message"`
+	EOLFrom         string            `json:"eol_from,omitempty"`
+	DaysToEOL       *int              `json:"days_to_eol,omitempty"`
+	Threshold       string            `json:"threshold,omitempty"`
+	LatestVersion   string            `json:"latest_version"`
+	PreviousVersion string            `json:"previous_version,omitempty"`
+	Installed       []string          `json:"installed,omitempty"`
+	Link            string            `json:"link,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Time            time.Time         `json:"time"`
+
+	// key deduplicates the event per webhook
+	key string
+}
+
+// evaluate returns the events of a product that were not sent to the webhook
+// yet. EOL reached events are skipped for products tracking all releases, as
+// every historic release cycle would fire one.
+func evaluate(status collector.ProductStatus, allReleases bool, thresholds []model.Duration, sent *webhookState, now time.Time) []Event {
+	if status.LastSuccess.IsZero() {
+		return nil
+	}
+
+	installed := make(map[string][]string)
+	for _, version := range status.Installed {
+		if rel, ok := lifecycle.MatchRelease(version, status.Releases); ok {
+			installed[rel.ReleaseCycleName] = append(installed[rel.ReleaseCycleName], version)
+		}
+	}
+
+	sorted := slices.Clone(thresholds)
+	slices.Sort(sorted)
+
+	var events []Event
+	for _, rel := range status.Releases {
+		// With installed versions only their release cycles are relevant
+		if len(status.Installed) > 0 && len(installed[rel.ReleaseCycleName]) == 0 {
+			continue
+		}
+
+		base := Event{
+			Product:       status.Name,
+			ReleaseCycle:  rel.ReleaseCycleName,
+			LatestVersion: rel.LatestVersion,
+			Installed:     installed[rel.ReleaseCycleName],
+			Link:          status.Link,
+			Labels:        status.Labels,
+			Time:          now,
+		}
+		if days, ok := lifecycle.DaysToEOL(rel, now); ok {
+			base.EOLFrom = rel.EOLFrom.Format(dateLayout)
+			base.DaysToEOL = &days
+		}
+
+		if event, ok := latestVersionEvent(base, sent); ok {
+			events = append(events, event)
+		}
+		if event, ok := eolEvent(base, rel, allReleases, sorted, now); ok && !sent.sent(event.key) {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// latestVersionEvent compares the latest version with the one last seen by the
+// webhook. The first version seen is only recorded.
+func latestVersionEvent(base Event, sent *webhookState) (Event, bool) {
+	cycle := base.Product + "/" + base.ReleaseCycle
+	previous, ok := sent.Latest[cycle]
+	if !ok {
+		sent.Latest[cycle] = base.LatestVersion
+		return Event{}, false
+	}
+	if previous == base.LatestVersion {
+		return Event{}, false
+	}
+
+	event := base
+	event.Type = EventNewLatestVersion
+	event.PreviousVersion = previous
+	event.Message = fmt.Sprintf("%s %s: new latest version %s (was %s)", base.Product, base.ReleaseCycle, base.LatestVersion, previous)
+	event.key = fmt.Sprintf("%s/%s/%s", event.Type, cycle, base.LatestVersion)
+	return event, true
+}
+
+// eolEvent returns the EOL reached event, or the EOL soon event of the smallest
+// threshold crossed. The EOL date is part of the key, so a moved date notifies
+// again.
+func eolEvent(base Event, rel endoflife.ReleaseDetails, allReleases bool, thresholds []model.Duration, now time.Time) (Event, bool) {
+	event := base
+	cycle := base.Product + "/" + base.ReleaseCycle
+
+	if lifecycle.PhaseAt(rel, now) == lifecycle.PhaseEOL {
+		if allReleases {
+			return Event{}, false
+		}
+		event.Type = EventEOLReached
+		event.Message = fmt.Sprintf("%s %s reached end-of-life", base.Product, base.ReleaseCycle)
+		if base.EOLFrom != "" {
+			event.Message += " on " + base.EOLFrom
+		}
+		event.key = fmt.Sprintf("%s/%s", event.Type, cycle)
+		return event, true
+	}
+
+	if base.DaysToEOL == nil {
+		return Event{}, false
+	}
+	for _, threshold := range thresholds {
+		if rel.EOLFrom.After(now.Add(time.Duration(threshold))) {
+			continue
+		}
+		event.Type = EventEOLSoon
+		event.Threshold = threshold.String()
+		event.Message = fmt.Sprintf("%s %s reaches end-of-life in %d days on %s", base.Product, base.ReleaseCycle, *base.DaysToEOL, base.EOLFrom)
+		event.key = fmt.Sprintf("%s/%s/%s/%s", event.Type, cycle, event.Threshold, base.EOLFrom)
+		return event, true
+	}
+
+	return Event{}, false
+}
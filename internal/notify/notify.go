@@ -0,0 +1,258 @@
+// Package notify posts lifecycle events of the tracked release cycles to
+// webhooks, as a lightweight alternative to Alertmanager.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+)
+
+// Templates are the built-in payload templates.
+var Templates = map[string]string{
+	"generic": `{{ json . }}`,
+	"slack":   `{"text": {{ if .Link }}{{ json (printf "<%s|%s> %s" .Link .Product .Message) }}{{ else }}{{ json .Message }}{{ end }}}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		out, err := json.Marshal(v)
+		return string(out), err
+	},
+}
+
+type webhook struct {
+	name       string
+	url        string
+	headers    map[string]string
+	template   *template.Template
+	events     []EventType
+	thresholds []model.Duration
+	products   []string
+	maxRetries int
+	timeout    time.Duration
+}
+
+// Notifier evaluates the cached product data after every refresh and sends
+// new events to the configured webhooks.
+type Notifier struct {
+	webhooks    []webhook
+	allReleases map[string]bool
+	stateFile   string
+	state       *state
+
+	client  *http.Client
+	backoff time.Duration // Delay before the first retry, doubled per retry
+	trigger chan struct{}
+}
+
+// New validates the webhooks of the configuration and loads the state file.
+func New(cfg config.Config) (*Notifier, error) {
+	n := &Notifier{
+		allReleases: make(map[string]bool, len(cfg.Products)),
+		stateFile:   cfg.Notifications.StateFile,
+		client:      &http.Client{},
+		backoff:     time.Second,
+		trigger:     make(chan struct{}, 1),
+	}
+	for _, product := range cfg.Products {
+		n.allReleases[product.Name] = product.AllReleases
+	}
+
+	for _, wh := range cfg.Notifications.Webhooks {
+		text, ok := Templates[wh.Template]
+		if !ok {
+			text = wh.Template
+		}
+		tmpl, err := template.New(wh.Name).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: invalid template: %w", wh.Name, err)
+		}
+
+		events := EventTypes
+		if len(wh.Events) > 0 {
+			events = nil
+			for _, e := range wh.Events {
+				if !slices.Contains(EventTypes, EventType(e)) {
+					return nil, fmt.Errorf("webhook %s: invalid event %q, must be one of %v", wh.Name, e, EventTypes)
+				}
+				events = append(events, EventType(e))
+			}
+		}
+
+		maxRetries := 0
+		if wh.MaxRetries != nil {
+			maxRetries = *wh.MaxRetries
+		}
+
+		headers := make(map[string]string, len(wh.Headers))
+		for k, v := range wh.Headers {
+			headers[k] = os.ExpandEnv(v)
+		}
+
+		n.webhooks = append(n.webhooks, webhook{
+			name:       wh.Name,
+			url:        os.ExpandEnv(wh.URL),
+			headers:    headers,
+			template:   tmpl,
+			events:     events,
+			thresholds: wh.Thresholds,
+			products:   wh.Products,
+			maxRetries: maxRetries,
+			timeout:    time.Duration(wh.Timeout),
+		})
+	}
+
+	s, err := loadState(n.stateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification state: %w", err)
+	}
+	n.state = s
+
+	return n, nil
+}
+
+// Trigger schedules a notification run without blocking, e.g. after a refresh.
+func (n *Notifier) Trigger() {
+	select {
+	case n.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends notifications for the products returned by products whenever it
+// is triggered, until ctx is cancelled.
+func (n *Notifier) Run(ctx context.Context, products func() []collector.ProductStatus) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.trigger:
+		}
+
+		if err := n.Notify(ctx, products(), time.Now()); err != nil {
+			slog.Warn("Failed to send notifications", "error", err)
+		}
+	}
+}
+
+// Notify sends the events of statuses that were not sent yet and persists the
+// state. Events that fail to send are retried on the next call.
+func (n *Notifier) Notify(ctx context.Context, statuses []collector.ProductStatus, now time.Time) error {
+	var errs []error
+
+	for _, wh := range n.webhooks {
+		ws := n.state.webhook(wh.name)
+
+		for _, status := range statuses {
+			if len(wh.products) > 0 && !slices.Contains(wh.products, status.Name) {
+				continue
+			}
+
+			for _, event := range evaluate(status, n.allReleases[status.Name], wh.thresholds, ws, now) {
+				if !slices.Contains(wh.events, event.Type) {
+					// Keep track of the latest version, even if not notified
+					if event.Type == EventNewLatestVersion {
+						ws.Latest[event.Product+"/"+event.ReleaseCycle] = event.LatestVersion
+					}
+					continue
+				}
+
+				if err := n.send(ctx, wh, event); err != nil {
+					errs = append(errs, fmt.Errorf("webhook %s: %s: %w", wh.name, event.key, err))
+					continue
+				}
+				slog.Info("Sent notification", "webhook", wh.name, "type", event.Type, "product_name", event.Product, "release_cycle_name", event.ReleaseCycle)
+
+				ws.Sent[event.key] = now
+				if event.Type == EventNewLatestVersion {
+					ws.Latest[event.Product+"/"+event.ReleaseCycle] = event.LatestVersion
+				}
+			}
+		}
+	}
+
+	if err := n.state.save(n.stateFile); err != nil {
+		errs = append(errs, fmt.Errorf("failed to save notification state: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// send posts the rendered event, retrying network errors, 429 and 5xx
+// responses with exponential backoff.
+func (n *Notifier) send(ctx context.Context, wh webhook, event Event) error {
+	var buf bytes.Buffer
+	if err := wh.template.Execute(&buf, event); err != nil {
+		return fmt.Errorf("failed to render payload: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return fmt.Errorf("payload is not valid JSON: %s", buf.String())
+	}
+
+	backoff := n.backoff
+	var err error
+	for attempt := 0; attempt <= wh.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		retry, err = n.post(ctx, wh, buf.Bytes())
+		if err == nil || !retry {
+			return err
+		}
+		slog.Debug("Retrying notification", "webhook", wh.name, "attempt", attempt+1, "error", err)
+	}
+	return err
+}
+
+// post sends a single request and reports whether a failure is worth retrying.
+func (n *Notifier) post(ctx context.Context, wh webhook, payload []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, wh.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range wh.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if err := resp.Body.Close(); err != nil {
+			slog.Warn("Error while closing the response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned non-OK status: %s", resp.Status)
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+func TestNotify(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Notify Suite")
+}
+
+// receiver records the payloads posted to it, failing the first failures requests.
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	requests int
+	payloads []map[string]any
+	headers  []http.Header
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.requests++
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	payload := map[string]any{}
+	Expect(json.Unmarshal(body, &payload)).To(Succeed())
+	rc.payloads = append(rc.payloads, payload)
+	rc.headers = append(rc.headers, r.Header.Clone())
+}
+
+var _ = Describe("Notify Suite", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	var (
+		rc        *receiver
+		srv       *httptest.Server
+		stateFile string
+	)
+
+	BeforeEach(func() {
+		rc = &receiver{}
+		srv = httptest.NewServer(rc)
+		DeferCleanup(srv.Close)
+		stateFile = filepath.Join(GinkgoT().TempDir(), "state.json")
+	})
+
+	newNotifier := func(template string, events ...string) *Notifier {
+		retries := 2
+		cfg := config.Config{
+			Products: []config.Product{{Name: "mongo"}, {Name: "ubuntu", AllReleases: true}},
+			Notifications: config.Notifications{
+				StateFile: stateFile,
+				Webhooks: []config.Webhook{{
+					Name:       "test",
+					URL:        srv.URL,
+					Template:   template,
+					Headers:    map[string]string{"Authorization": "Bearer ${NOTIFY_TEST_TOKEN}"},
+					Events:     events,
+					Thresholds: []model.Duration{model.Duration(90 * day), model.Duration(30 * day)},
+					MaxRetries: &retries,
+					Timeout:    model.Duration(time.Second),
+				}},
+			},
+		}
+		n, err := New(cfg)
+		Expect(err).To(BeNil())
+		n.backoff = time.Millisecond
+		return n
+	}
+
+	mongo := func(eolIn time.Duration, latest string) collector.ProductStatus {
+		return collector.ProductStatus{
+			Name:        "mongo",
+			LastSuccess: now,
+			Labels:      map[string]string{"team": "data"},
+			Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "7.0", EOLFrom: now.Add(eolIn), LatestVersion: latest},
+			},
+		}
+	}
+
+	It("should notify the smallest threshold crossed once", func() {
+		GinkgoT().Setenv("NOTIFY_TEST_TOKEN", "secret")
+		n := newNotifier("generic")
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(100*day, "7.0.1")}, now)).To(Succeed())
+		Expect(rc.payloads).To(BeEmpty())
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(20*day, "7.0.1")}, now)).To(Succeed())
+		Expect(rc.payloads).To(HaveLen(1))
+		Expect(rc.payloads[0]).To(HaveKeyWithValue("type", "eol_soon"))
+		Expect(rc.payloads[0]).To(HaveKeyWithValue("threshold", "30d"))
+		Expect(rc.payloads[0]).To(HaveKeyWithValue("days_to_eol", BeNumerically("==", 20)))
+		Expect(rc.payloads[0]).To(HaveKeyWithValue("labels", map[string]any{"team": "data"}))
+		Expect(rc.headers[0].Get("Authorization")).To(Equal("Bearer secret"))
+
+		// Deduplicated, also after a restart
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(20*day, "7.0.1")}, now)).To(Succeed())
+		Expect(newNotifier("generic").Notify(context.Background(), []collector.ProductStatus{mongo(20*day, "7.0.1")}, now)).To(Succeed())
+		Expect(rc.payloads).To(HaveLen(1))
+	})
+
+	It("should notify new latest versions and reached EOL", func() {
+		n := newNotifier("generic")
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(365*day, "7.0.1")}, now)).To(Succeed())
+		Expect(rc.payloads).To(BeEmpty())
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(365*day, "7.0.2")}, now)).To(Succeed())
+		Expect(rc.payloads).To(HaveLen(1))
+		Expect(rc.payloads[0]).To(HaveKeyWithValue("type", "new_latest_version"))
+		Expect(rc.payloads[0]).To(HaveKeyWithValue("previous_version", "7.0.1"))
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(-day, "7.0.2")}, now)).To(Succeed())
+		Expect(rc.payloads).To(HaveLen(2))
+		Expect(rc.payloads[1]).To(HaveKeyWithValue("type", "eol_reached"))
+	})
+
+	It("should not notify reached EOL of products tracking all releases", func() {
+		n := newNotifier("generic")
+		ubuntu := mongo(-day, "7.0.1")
+		ubuntu.Name = "ubuntu"
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{ubuntu}, now)).To(Succeed())
+		Expect(rc.payloads).To(BeEmpty())
+	})
+
+	It("should only send the configured events", func() {
+		n := newNotifier("generic", "new_latest_version")
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(20*day, "7.0.1")}, now)).To(Succeed())
+		Expect(rc.payloads).To(BeEmpty())
+	})
+
+	It("should retry failed requests", func() {
+		n := newNotifier("slack")
+		rc.failures = 2
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(20*day, "7.0.1")}, now)).To(Succeed())
+		Expect(rc.requests).To(Equal(3))
+		Expect(rc.payloads).To(HaveLen(1))
+		Expect(rc.payloads[0]).To(HaveKeyWithValue("text", "mongo 7.0 reaches end-of-life in 20 days on 2026-01-21"))
+	})
+
+	It("should send again when retries are exhausted", func() {
+		n := newNotifier("generic")
+		rc.failures = 3
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(20*day, "7.0.1")}, now)).NotTo(Succeed())
+		Expect(rc.payloads).To(BeEmpty())
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(20*day, "7.0.1")}, now)).To(Succeed())
+		Expect(rc.payloads).To(HaveLen(1))
+	})
+
+	It("should render custom templates", func() {
+		n := newNotifier(`{"summary": {{ json .Message }}, "product": "{{ .Product }}"}`)
+
+		Expect(n.Notify(context.Background(), []collector.ProductStatus{mongo(20*day, "7.0.1")}, now)).To(Succeed())
+		Expect(rc.payloads[0]).To(HaveKeyWithValue("product", "mongo"))
+	})
+
+	It("should reject invalid events and templates", func() {
+		cfg := config.Config{Notifications: config.Notifications{Webhooks: []config.Webhook{{Name: "a", URL: "http://a", Template: "generic", Events: []string{"soon"}}}}}
+		_, err := New(cfg)
+		Expect(err).NotTo(BeNil())
+
+		cfg.Notifications.Webhooks[0].Events = nil
+		cfg.Notifications.Webhooks[0].Template = "{{ .Product "
+		_, err = New(cfg)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/storage"
+)
+
+// state is persisted to the state file, so notifications are not sent again
+// after a restart.
+type state struct {
+	Webhooks map[string]*webhookState `json:"webhooks"`
+}
+
+type webhookState struct {
+	// Sent maps event keys to the time they were sent
+	Sent map[string]time.Time `json:"sent"`
+	// Latest maps product/release cycle to the latest version last seen
+	Latest map[string]string `json:"latest"`
+}
+
+func (s *webhookState) sent(key string) bool {
+	_, ok := s.Sent[key]
+	return ok
+}
+
+func (s *state) webhook(name string) *webhookState {
+	if s.Webhooks == nil {
+		s.Webhooks = make(map[string]*webhookState)
+	}
+	ws, ok := s.Webhooks[name]
+	if !ok {
+		ws = &webhookState{}
+		s.Webhooks[name] = ws
+	}
+	if ws.Sent == nil {
+		ws.Sent = make(map[string]time.Time)
+	}
+	if ws.Latest == nil {
+		ws.Latest = make(map[string]string)
+	}
+	return ws
+}
+
+// loadState reads the state file, a missing file is an empty state.
+func loadState(path string) (*state, error) {
+	s := &state{}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// save writes the state file atomically, so a crash never leaves a partial file.
+func (s *state) save(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return storage.WriteFileAtomic(path, data)
+}
@@ -0,0 +1,88 @@
+// Package otlp periodically exports the collector's metrics to an
+// OpenTelemetry Collector over OTLP, for pipelines without Prometheus scraping.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/veerendra2/gopackages/version"
+	prometheusbridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+const serviceName = "endoflife_exporter"
+
+// Config holds the OTLP export flags.
+type Config struct {
+	Endpoint string            `env:"ENDPOINT" help:"OTLP endpoint to export metrics to, host:port or URL. Disabled if empty."`
+	Protocol string            `env:"PROTOCOL" enum:"grpc,http/protobuf" default:"grpc" help:"OTLP protocol. Must be \"grpc\" or \"http/protobuf\"."`
+	Insecure bool              `env:"INSECURE" help:"Disable TLS for the OTLP endpoint."`
+	Headers  map[string]string `env:"HEADERS" help:"Headers sent with every export, e.g. authorization=\"Bearer token\"."`
+	Interval time.Duration     `env:"INTERVAL" default:"1m" help:"How often metrics are exported."`
+	Timeout  time.Duration     `env:"TIMEOUT" default:"10s" help:"Timeout of a single export."`
+}
+
+// Start exports the metrics of gatherer every interval until the returned
+// shutdown function is called, which also exports the metrics one last time.
+// Metric names and labels are kept, gauges become OTel gauges and counters
+// cumulative monotonic sums.
+func Start(ctx context.Context, cfg Config, gatherer prometheus.Gatherer) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(cfg.Interval),
+		sdkmetric.WithTimeout(cfg.Timeout),
+		sdkmetric.WithProducer(prometheusbridge.NewMetricProducer(prometheusbridge.WithGatherer(gatherer))),
+	)
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version.Version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res))
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	// A URL sets scheme and path, host:port uses the protocol defaults
+	isURL := strings.Contains(cfg.Endpoint, "://")
+
+	if cfg.Protocol == "http/protobuf" {
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithTimeout(cfg.Timeout), otlpmetrichttp.WithHeaders(cfg.Headers)}
+		if isURL {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(cfg.Endpoint))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithTimeout(cfg.Timeout), otlpmetricgrpc.WithHeaders(cfg.Headers)}
+	if isURL {
+		opts = append(opts, otlpmetricgrpc.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
@@ -0,0 +1,181 @@
+package otlp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOTLP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OTLP Suite")
+}
+
+// receiver is an in-process OTLP metrics receiver.
+type receiver struct {
+	collectormetrics.UnimplementedMetricsServiceServer
+
+	mu       sync.Mutex
+	requests []*collectormetrics.ExportMetricsServiceRequest
+	headers  []string
+}
+
+func (rc *receiver) Export(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	rc.record(req, md.Get("authorization"))
+	return &collectormetrics.ExportMetricsServiceResponse{}, nil
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := &collectormetrics.ExportMetricsServiceRequest{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rc.record(req, r.Header.Values("Authorization"))
+
+	out, _ := proto.Marshal(&collectormetrics.ExportMetricsServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(out)
+}
+
+func (rc *receiver) record(req *collectormetrics.ExportMetricsServiceRequest, headers []string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, req)
+	rc.headers = append(rc.headers, headers...)
+}
+
+// metrics returns the received metrics by name.
+func (rc *receiver) metrics() map[string]*metricspb.Metric {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	metrics := make(map[string]*metricspb.Metric)
+	for _, req := range rc.requests {
+		for _, rm := range req.GetResourceMetrics() {
+			for _, sm := range rm.GetScopeMetrics() {
+				for _, m := range sm.GetMetrics() {
+					metrics[m.GetName()] = m
+				}
+			}
+		}
+	}
+	return metrics
+}
+
+func attributes(dp *metricspb.NumberDataPoint) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range dp.GetAttributes() {
+		attrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+	}
+	return attrs
+}
+
+var _ = Describe("OTLP Suite", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newRegistry := func() *prometheus.Registry {
+		exporter, err := collector.NewExporter(config.Config{Products: []config.Product{{Name: "mongo", Labels: map[string]string{"team": "data"}}}})
+		Expect(err).To(BeNil())
+		exporter.Restore(collector.Snapshot{Products: map[string]collector.ProductSnapshot{
+			"mongo": {
+				Releases:    []endoflife.ReleaseDetails{{ReleaseCycleName: "7.0", EOLFrom: now, LatestVersion: "7.0.12"}},
+				LastAttempt: now,
+				LastSuccess: now,
+			},
+		}})
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter)
+		return registry
+	}
+
+	expectMetrics := func(rc *receiver) {
+		metrics := rc.metrics()
+
+		info := metrics[collector.ProductInfoMetric.Name]
+		Expect(info).NotTo(BeNil())
+		Expect(info.GetGauge().GetDataPoints()).To(HaveLen(1))
+		Expect(attributes(info.GetGauge().GetDataPoints()[0])).To(Equal(map[string]string{
+			"is_eol":             "false",
+			"is_lts":             "false",
+			"is_maintained":      "false",
+			"latest_version":     "7.0.12",
+			"product_name":       "mongo",
+			"release_cycle_name": "7.0",
+			"team":               "data",
+		}))
+
+		eol := metrics[collector.EolFromTimestampSecondsMetric.Name]
+		Expect(eol.GetGauge().GetDataPoints()[0].GetAsDouble()).To(Equal(float64(now.Unix())))
+
+		changes := metrics[collector.ChangesTotalMetric.Name]
+		Expect(changes.GetSum().GetIsMonotonic()).To(BeTrue())
+		Expect(changes.GetSum().GetAggregationTemporality()).To(Equal(metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE))
+
+		Expect(rc.headers).To(ContainElement("Bearer secret"))
+	}
+
+	It("should export over gRPC", func() {
+		rc := &receiver{}
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		srv := grpc.NewServer()
+		collectormetrics.RegisterMetricsServiceServer(srv, rc)
+		go func() { _ = srv.Serve(lis) }()
+		DeferCleanup(srv.Stop)
+
+		shutdown, err := Start(context.Background(), Config{
+			Endpoint: lis.Addr().String(),
+			Protocol: "grpc",
+			Insecure: true,
+			Headers:  map[string]string{"authorization": "Bearer secret"},
+			Interval: time.Hour,
+			Timeout:  5 * time.Second,
+		}, newRegistry())
+		Expect(err).To(BeNil())
+
+		// Shutdown exports one last time
+		Expect(shutdown(context.Background())).To(Succeed())
+		expectMetrics(rc)
+	})
+
+	It("should export over HTTP", func() {
+		rc := &receiver{}
+		srv := httptest.NewServer(rc)
+		DeferCleanup(srv.Close)
+
+		shutdown, err := Start(context.Background(), Config{
+			Endpoint: srv.URL + "/v1/metrics",
+			Protocol: "http/protobuf",
+			Insecure: true,
+			Headers:  map[string]string{"Authorization": "Bearer secret"},
+			Interval: 50 * time.Millisecond,
+			Timeout:  5 * time.Second,
+		}, newRegistry())
+		Expect(err).To(BeNil())
+		DeferCleanup(shutdown, context.Background())
+
+		Eventually(func() int { return len(rc.metrics()) }).ShouldNot(BeZero())
+		expectMetrics(rc)
+	})
+})
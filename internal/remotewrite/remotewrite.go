@@ -0,0 +1,187 @@
+// Package remotewrite pushes the collector's metrics to a Prometheus remote
+// write endpoint, for sites where Prometheus cannot scrape the exporter.
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/veerendra2/gopackages/version"
+)
+
+// Config holds the remote write flags.
+type Config struct {
+	URL            string            `name:"url" env:"URL" help:"Remote write endpoint, e.g. https://prometheus.example.com/api/v1/write. Disabled if empty."`
+	Headers        map[string]string `env:"HEADERS" help:"Headers sent with every request, e.g. Authorization=\"Bearer token\"."`
+	Username       string            `env:"USERNAME" help:"Username for basic authentication."`
+	Password       string            `env:"PASSWORD" help:"Password for basic authentication."`
+	ExternalLabels map[string]string `env:"EXTERNAL_LABELS" help:"Labels added to every series, e.g. site=edge-1."`
+	Interval       time.Duration     `env:"INTERVAL" default:"1m" help:"How often metrics are sent."`
+	Timeout        time.Duration     `env:"TIMEOUT" default:"30s" help:"Timeout of a single request."`
+	QueueSize      int               `env:"QUEUE_SIZE" default:"60" help:"Number of failed requests kept for retrying, the oldest are dropped first."`
+}
+
+// Sender gathers metrics every interval and sends them with the remote write
+// 1.0 protocol. Requests that fail with a retryable error are queued and sent
+// in order before the next request.
+type Sender struct {
+	cfg      Config
+	gatherer prometheus.Gatherer
+	client   *http.Client
+
+	// Encoded and compressed requests waiting to be sent
+	queue [][]byte
+}
+
+// New returns a sender for the metrics of gatherer.
+func New(cfg Config, gatherer prometheus.Gatherer) *Sender {
+	return &Sender{cfg: cfg, gatherer: gatherer, client: &http.Client{}}
+}
+
+// Run sends the metrics every interval until ctx is cancelled.
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Send(ctx, time.Now()); err != nil {
+			slog.Warn("Failed to send metrics over remote write", "error", err, "queued", len(s.queue))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Send gathers the metrics with timestamp now, queues them and sends the
+// queue. It is not safe for concurrent use.
+func (s *Sender) Send(ctx context.Context, now time.Time) error {
+	mfs, err := s.gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	series := toTimeSeries(mfs, s.cfg.ExternalLabels, now)
+	if len(series) > 0 {
+		s.queue = append(s.queue, snappy.Encode(nil, marshalWriteRequest(series)))
+	}
+	if over := len(s.queue) - s.cfg.QueueSize; over > 0 {
+		slog.Warn("Remote write queue is full, dropping oldest requests", "dropped", over)
+		s.queue = s.queue[over:]
+	}
+
+	for len(s.queue) > 0 {
+		retry, err := s.post(ctx, s.queue[0])
+		if err != nil && retry {
+			return err
+		}
+		if err != nil {
+			slog.Warn("Dropping rejected remote write request", "error", err)
+		}
+		s.queue = s.queue[1:]
+	}
+	return nil
+}
+
+// Queued returns the number of requests waiting to be retried.
+func (s *Sender) Queued() int {
+	return len(s.queue)
+}
+
+// post sends a single request and reports whether a failure is worth retrying.
+func (s *Sender) post(ctx context.Context, payload []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "endoflife_exporter/"+version.Version)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if s.cfg.Username != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if err := resp.Body.Close(); err != nil {
+			slog.Warn("Error while closing the response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("remote write returned non-OK status: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// toTimeSeries converts gauges, counters and untyped metrics to series with
+// sorted labels. Empty labels are dropped, as they are equal to unset labels
+// and rejected by some receivers. External labels don't override labels of
+// the metric.
+func toTimeSeries(mfs []*dto.MetricFamily, externalLabels map[string]string, now time.Time) []timeSeries {
+	var series []timeSeries
+
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			var value float64
+			switch mf.GetType() {
+			case dto.MetricType_GAUGE:
+				value = m.GetGauge().GetValue()
+			case dto.MetricType_COUNTER:
+				value = m.GetCounter().GetValue()
+			case dto.MetricType_UNTYPED:
+				value = m.GetUntyped().GetValue()
+			default:
+				continue
+			}
+
+			labels := []label{{Name: "__name__", Value: mf.GetName()}}
+			for _, lp := range m.GetLabel() {
+				if lp.GetValue() == "" {
+					continue
+				}
+				labels = append(labels, label{Name: lp.GetName(), Value: lp.GetValue()})
+			}
+			for name, value := range externalLabels {
+				if !slices.ContainsFunc(labels, func(l label) bool { return l.Name == name }) {
+					labels = append(labels, label{Name: name, Value: value})
+				}
+			}
+			slices.SortFunc(labels, func(a, b label) int { return strings.Compare(a.Name, b.Name) })
+
+			series = append(series, timeSeries{
+				Labels:  labels,
+				Samples: []sample{{Value: value, Timestamp: now.UnixMilli()}},
+			})
+		}
+	}
+
+	return series
+}
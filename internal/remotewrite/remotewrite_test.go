@@ -0,0 +1,192 @@
+package remotewrite
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestRemoteWrite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Remote Write Suite")
+}
+
+// fields splits a protobuf message into its fields by number.
+func fields(b []byte) map[protowire.Number][][]byte {
+	out := make(map[protowire.Number][][]byte)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		Expect(n).To(BeNumerically(">", 0))
+		b = b[n:]
+
+		var value []byte
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+		case protowire.Fixed64Type:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			value = b[:n]
+		case protowire.VarintType:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			value = b[:n]
+		}
+		Expect(n).To(BeNumerically(">", 0))
+		out[num] = append(out[num], value)
+		b = b[n:]
+	}
+	return out
+}
+
+// unmarshalWriteRequest decodes a WriteRequest, the inverse of marshalWriteRequest.
+func unmarshalWriteRequest(b []byte) []timeSeries {
+	var series []timeSeries
+	for _, tsb := range fields(b)[1] {
+		ts := timeSeries{}
+		tsFields := fields(tsb)
+		for _, lb := range tsFields[1] {
+			lf := fields(lb)
+			ts.Labels = append(ts.Labels, label{Name: string(lf[1][0]), Value: string(lf[2][0])})
+		}
+		for _, sb := range tsFields[2] {
+			sf := fields(sb)
+			bits, _ := protowire.ConsumeFixed64(sf[1][0])
+			timestamp, _ := protowire.ConsumeVarint(sf[2][0])
+			ts.Samples = append(ts.Samples, sample{Value: math.Float64frombits(bits), Timestamp: int64(timestamp)})
+		}
+		series = append(series, ts)
+	}
+	return series
+}
+
+// receiver decodes remote write requests, failing with the queued status codes first.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests [][]timeSeries
+	headers  []http.Header
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if len(rc.statuses) > 0 {
+		status := rc.statuses[0]
+		rc.statuses = rc.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+
+	compressed, _ := io.ReadAll(r.Body)
+	body, err := snappy.Decode(nil, compressed)
+	Expect(err).To(BeNil())
+	rc.requests = append(rc.requests, unmarshalWriteRequest(body))
+	rc.headers = append(rc.headers, r.Header.Clone())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+var _ = Describe("Remote Write Suite", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var (
+		rc     *receiver
+		srv    *httptest.Server
+		sender *Sender
+	)
+
+	BeforeEach(func() {
+		registry := prometheus.NewRegistry()
+		info := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "endoflife_product_info", Help: "Info."}, []string{"product_name", "team"})
+		info.WithLabelValues("mongo", "data").Set(1)
+		info.WithLabelValues("redis", "").Set(1)
+		changes := prometheus.NewCounter(prometheus.CounterOpts{Name: "endoflife_changes_total", Help: "Changes.", ConstLabels: prometheus.Labels{"site": "metric"}})
+		changes.Add(2)
+		registry.MustRegister(info, changes)
+
+		rc = &receiver{}
+		srv = httptest.NewServer(rc)
+		DeferCleanup(srv.Close)
+
+		sender = New(Config{
+			URL:            srv.URL,
+			Headers:        map[string]string{"X-Scope-OrgID": "edge"},
+			Username:       "user",
+			Password:       "secret",
+			ExternalLabels: map[string]string{"site": "edge-1"},
+			Timeout:        time.Second,
+			QueueSize:      2,
+		}, registry)
+	})
+
+	It("should send snappy compressed series with sorted labels", func() {
+		Expect(sender.Send(context.Background(), now)).To(Succeed())
+
+		Expect(rc.requests).To(HaveLen(1))
+		Expect(rc.requests[0]).To(ConsistOf(
+			timeSeries{
+				Labels:  []label{{"__name__", "endoflife_changes_total"}, {"site", "metric"}},
+				Samples: []sample{{Value: 2, Timestamp: now.UnixMilli()}},
+			},
+			timeSeries{
+				Labels:  []label{{"__name__", "endoflife_product_info"}, {"product_name", "mongo"}, {"site", "edge-1"}, {"team", "data"}},
+				Samples: []sample{{Value: 1, Timestamp: now.UnixMilli()}},
+			},
+			timeSeries{
+				Labels:  []label{{"__name__", "endoflife_product_info"}, {"product_name", "redis"}, {"site", "edge-1"}},
+				Samples: []sample{{Value: 1, Timestamp: now.UnixMilli()}},
+			},
+		))
+
+		headers := rc.headers[0]
+		Expect(headers.Get("Content-Encoding")).To(Equal("snappy"))
+		Expect(headers.Get("Content-Type")).To(Equal("application/x-protobuf"))
+		Expect(headers.Get("X-Prometheus-Remote-Write-Version")).To(Equal("0.1.0"))
+		Expect(headers.Get("X-Scope-OrgID")).To(Equal("edge"))
+		Expect(headers.Get("Authorization")).To(HavePrefix("Basic "))
+	})
+
+	It("should queue failed requests and retry them in order", func() {
+		rc.statuses = []int{http.StatusServiceUnavailable}
+
+		Expect(sender.Send(context.Background(), now)).NotTo(Succeed())
+		Expect(sender.Queued()).To(Equal(1))
+
+		Expect(sender.Send(context.Background(), now.Add(time.Minute))).To(Succeed())
+		Expect(sender.Queued()).To(Equal(0))
+		Expect(rc.requests).To(HaveLen(2))
+		Expect(rc.requests[0][0].Samples[0].Timestamp).To(Equal(now.UnixMilli()))
+		Expect(rc.requests[1][0].Samples[0].Timestamp).To(Equal(now.Add(time.Minute).UnixMilli()))
+	})
+
+	It("should drop the oldest requests when the queue is full", func() {
+		rc.statuses = []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests}
+
+		for i := range 3 {
+			Expect(sender.Send(context.Background(), now.Add(time.Duration(i)*time.Minute))).NotTo(Succeed())
+		}
+		Expect(sender.Queued()).To(Equal(2))
+
+		Expect(sender.Send(context.Background(), now.Add(3*time.Minute))).To(Succeed())
+		Expect(rc.requests).To(HaveLen(2))
+		Expect(rc.requests[0][0].Samples[0].Timestamp).To(Equal(now.Add(2 * time.Minute).UnixMilli()))
+	})
+
+	It("should drop rejected requests", func() {
+		rc.statuses = []int{http.StatusBadRequest}
+
+		Expect(sender.Send(context.Background(), now)).To(Succeed())
+		Expect(sender.Queued()).To(Equal(0))
+		Expect(rc.requests).To(BeEmpty())
+	})
+})
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/prometheus/common/model"
+)
+
+// Write renders the report in the given format.
+func (r Result) Write(w io.Writer, format string) error {
+	switch format {
+	case "table":
+		return r.WriteTable(w)
+	case "json":
+		return r.WriteJSON(w)
+	case "markdown":
+		return r.WriteMarkdown(w)
+	case "sarif":
+		return r.WriteSARIF(w)
+	case "junit":
+		return r.WriteJUnit(w)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+// WriteTable renders the findings as an aligned plain text table.
+func (r Result) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "STATUS\tPRODUCT\tRELEASE\tPHASE\tEOL\tLATEST\tINSTALLED\tMESSAGE")
+	for _, f := range r.Findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			strings.ToUpper(string(f.Status)),
+			f.Product,
+			orDash(f.ReleaseCycle),
+			orDash(string(f.Phase)),
+			orDash(f.EOLFrom),
+			orDash(f.LatestVersion),
+			orDash(strings.Join(f.Installed, ", ")),
+			f.Message,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(w, "\n"+r.summary())
+	return err
+}
+
+// WriteMarkdown renders the findings as a GitHub flavored markdown table,
+// e.g. for pull request comments or job summaries.
+func (r Result) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("## End-of-Life Check\n\n")
+	b.WriteString("| Status | Product | Release | Phase | EOL | Latest | Installed | Message |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "| %s %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			statusEmoji(f.Status),
+			f.Status,
+			escapeMarkdown(f.Product),
+			escapeMarkdown(orDash(f.ReleaseCycle)),
+			orDash(string(f.Phase)),
+			orDash(f.EOLFrom),
+			escapeMarkdown(orDash(f.LatestVersion)),
+			escapeMarkdown(orDash(strings.Join(f.Installed, ", "))),
+			escapeMarkdown(f.Message),
+		)
+	}
+	b.WriteString("\n" + r.summary() + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type jsonResult struct {
+	GeneratedAt string         `json:"generated_at"`
+	Warn        string         `json:"warn"`
+	Fail        string         `json:"fail"`
+	Status      Status         `json:"status"`
+	Summary     map[Status]int `json:"summary"`
+	Findings    []Finding      `json:"findings"`
+}
+
+// WriteJSON renders the report as an indented JSON document.
+func (r Result) WriteJSON(w io.Writer) error {
+	findings := r.Findings
+	if findings == nil {
+		findings = []Finding{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonResult{
+		GeneratedAt: r.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Warn:        model.Duration(r.Thresholds.Warn).String(),
+		Fail:        model.Duration(r.Thresholds.Fail).String(),
+		Status:      r.Status(),
+		Summary: map[Status]int{
+			StatusOK:    r.Count(StatusOK),
+			StatusWarn:  r.Count(StatusWarn),
+			StatusFail:  r.Count(StatusFail),
+			StatusError: r.Count(StatusError),
+		},
+		Findings: findings,
+	})
+}
+
+func (r Result) summary() string {
+	return fmt.Sprintf("%d ok, %d warn, %d fail, %d error",
+		r.Count(StatusOK), r.Count(StatusWarn), r.Count(StatusFail), r.Count(StatusError))
+}
+
+func statusEmoji(s Status) string {
+	switch s {
+	case StatusWarn:
+		return "⚠️"
+	case StatusFail, StatusError:
+		return "❌"
+	}
+	return "✅"
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit renders the findings as JUnit XML with one test suite per product
+// and one test case per release cycle. Failed and errored findings fail their
+// test case, warnings pass with the message in system-out.
+func (r Result) WriteJUnit(w io.Writer) error {
+	suites := junitTestSuites{Name: "endoflife"}
+	suiteIndex := make(map[string]int)
+
+	for _, f := range r.Findings {
+		idx, ok := suiteIndex[f.Product]
+		if !ok {
+			idx = len(suites.Suites)
+			suiteIndex[f.Product] = idx
+			suites.Suites = append(suites.Suites, junitTestSuite{
+				Name:      f.Product,
+				Timestamp: r.GeneratedAt.Format("2006-01-02T15:04:05"),
+			})
+		}
+		suite := &suites.Suites[idx]
+
+		tc := junitTestCase{
+			Name:      junitCaseName(f),
+			ClassName: "endoflife." + f.Product,
+		}
+		msg := &junitMessage{Message: f.Message, Type: string(f.Status), Text: junitDetails(f)}
+
+		switch f.Status {
+		case StatusFail:
+			tc.Failure = msg
+			suite.Failures++
+		case StatusError:
+			tc.Error = msg
+			suite.Errors++
+		case StatusWarn:
+			tc.SystemOut = "warning: " + f.Message
+		}
+
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitCaseName(f Finding) string {
+	switch {
+	case f.ReleaseCycle != "":
+		return f.ReleaseCycle
+	case len(f.Installed) > 0:
+		return "installed " + f.Installed[0]
+	}
+	return "fetch"
+}
+
+func junitDetails(f Finding) string {
+	details := fmt.Sprintf("product: %s\nrelease cycle: %s\nphase: %s\n", f.Product, orDash(f.ReleaseCycle), orDash(string(f.Phase)))
+	if f.EOLFrom != "" {
+		details += "eol: " + f.EOLFrom + "\n"
+	}
+	if f.LatestVersion != "" {
+		details += "latest version: " + f.LatestVersion + "\n"
+	}
+	return details
+}
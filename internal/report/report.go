@@ -0,0 +1,173 @@
+// Package report evaluates the tracked release cycles against EOL thresholds
+// and renders the result for CI systems.
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+const dateLayout = "2006-01-02"
+
+// Status is the result of checking a single release cycle.
+type Status string
+
+const (
+	StatusOK    Status = "ok"
+	StatusWarn  Status = "warn"  // EOL within the warn window
+	StatusFail  Status = "fail"  // EOL within the fail window or already EOL
+	StatusError Status = "error" // Product could not be fetched
+)
+
+// ExitCode returns the process exit code for the status.
+func (s Status) ExitCode() int {
+	switch s {
+	case StatusWarn:
+		return 1
+	case StatusFail, StatusError:
+		return 2
+	}
+	return 0
+}
+
+// Thresholds are the look ahead windows for warnings and failures.
+type Thresholds struct {
+	Warn time.Duration
+	Fail time.Duration
+}
+
+// Finding is the check result of a single release cycle, or of a product that
+// failed to fetch.
+type Finding struct {
+	Product       string          `json:"product"`
+	ReleaseCycle  string          `json:"release_cycle,omitempty"`
+	Phase         lifecycle.Phase `json:"phase,omitempty"`
+	EOLFrom       string          `json:"eol_from,omitempty"`
+	DaysToEOL     *int            `json:"days_to_eol,omitempty"`
+	LatestVersion string          `json:"latest_version,omitempty"`
+	Installed     []string        `json:"installed,omitempty"`
+	Status        Status          `json:"status"`
+	Message       string          `json:"message"`
+}
+
+// Result holds the findings of all tracked products.
+type Result struct {
+	GeneratedAt time.Time
+	Thresholds  Thresholds
+	Findings    []Finding
+
+	// ConfigFile and ProductLines locate the products for SARIF results
+	ConfigFile   string
+	ProductLines map[string]int
+}
+
+// Evaluate checks the release cycles of all products at now. When installed
+// versions are configured for a product, only their release cycles are
+// checked, otherwise all tracked release cycles are.
+func Evaluate(statuses []collector.ProductStatus, thresholds Thresholds, now time.Time) Result {
+	rep := Result{GeneratedAt: now, Thresholds: thresholds}
+
+	for _, status := range statuses {
+		if status.Err != nil {
+			rep.Findings = append(rep.Findings, Finding{
+				Product: status.Name,
+				Status:  StatusError,
+				Message: status.Err.Error(),
+			})
+		}
+
+		installed := make(map[string][]string)
+		for _, version := range status.Installed {
+			rel, ok := lifecycle.MatchRelease(version, status.Releases)
+			if !ok && len(status.Releases) == 0 {
+				// Already reported as error finding
+				continue
+			}
+			if !ok {
+				rep.Findings = append(rep.Findings, Finding{
+					Product:   status.Name,
+					Installed: []string{version},
+					Status:    StatusWarn,
+					Message:   fmt.Sprintf("no tracked release cycle matches installed version %s", version),
+				})
+				continue
+			}
+			installed[rel.ReleaseCycleName] = append(installed[rel.ReleaseCycleName], version)
+		}
+
+		for _, rel := range status.Releases {
+			if len(status.Installed) > 0 && len(installed[rel.ReleaseCycleName]) == 0 {
+				continue
+			}
+
+			rep.Findings = append(rep.Findings, evaluateRelease(status.Name, rel, installed[rel.ReleaseCycleName], thresholds, now))
+		}
+	}
+
+	return rep
+}
+
+// evaluateRelease checks a single release cycle at now.
+func evaluateRelease(product string, rel endoflife.ReleaseDetails, installed []string, thresholds Thresholds, now time.Time) Finding {
+	finding := Finding{
+		Product:       product,
+		ReleaseCycle:  rel.ReleaseCycleName,
+		Phase:         lifecycle.PhaseAt(rel, now),
+		LatestVersion: rel.LatestVersion,
+		Installed:     installed,
+		Status:        StatusOK,
+	}
+
+	days, hasDate := lifecycle.DaysToEOL(rel, now)
+	if hasDate {
+		finding.EOLFrom = rel.EOLFrom.Format(dateLayout)
+		finding.DaysToEOL = &days
+	}
+
+	switch {
+	case finding.Phase == lifecycle.PhaseEOL:
+		finding.Status = StatusFail
+		finding.Message = "reached end-of-life"
+		if hasDate {
+			finding.Message += " on " + finding.EOLFrom
+		}
+	case hasDate && !rel.EOLFrom.After(now.Add(thresholds.Fail)):
+		finding.Status = StatusFail
+		finding.Message = fmt.Sprintf("reaches end-of-life in %d days on %s", days, finding.EOLFrom)
+	case hasDate && !rel.EOLFrom.After(now.Add(thresholds.Warn)):
+		finding.Status = StatusWarn
+		finding.Message = fmt.Sprintf("reaches end-of-life in %d days on %s", days, finding.EOLFrom)
+	case hasDate:
+		finding.Message = "supported until " + finding.EOLFrom
+	default:
+		finding.Message = "end-of-life date unknown"
+	}
+
+	return finding
+}
+
+// Status returns the most severe status of all findings.
+func (r Result) Status() Status {
+	worst := StatusOK
+	for _, f := range r.Findings {
+		if f.Status.ExitCode() > worst.ExitCode() {
+			worst = f.Status
+		}
+	}
+	return worst
+}
+
+// Count returns the number of findings with the given status.
+func (r Result) Count(status Status) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Status == status {
+			n++
+		}
+	}
+	return n
+}
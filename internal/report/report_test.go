@@ -0,0 +1,218 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+func TestReport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Report Suite")
+}
+
+var _ = Describe("Report Suite", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	thresholds := Thresholds{Warn: 90 * 24 * time.Hour, Fail: 30 * 24 * time.Hour}
+
+	statuses := []collector.ProductStatus{
+		{
+			Name: "mongo",
+			Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "8.0", EOLFrom: now.AddDate(2, 0, 0), LatestVersion: "8.0.4"},
+				{ReleaseCycleName: "7.0", EOLFrom: now.AddDate(0, 0, 60), LatestVersion: "7.0.15"},
+				{ReleaseCycleName: "6.0", EOLFrom: now.AddDate(0, 0, 10), LatestVersion: "6.0.20"},
+				{ReleaseCycleName: "5.0", EOLFrom: now.AddDate(0, -6, 0), IsEol: true, LatestVersion: "5.0.30"},
+				{ReleaseCycleName: "4.4", EOLFrom: endoflife.UnknownDate, LatestVersion: "4.4.29"},
+			},
+		},
+	}
+
+	Context("When evaluating releases", func() {
+		It("should apply the warn and fail thresholds", func() {
+			rep := Evaluate(statuses, thresholds, now)
+
+			Expect(rep.Findings).To(HaveLen(5))
+			Expect(rep.Findings[0].Status).To(Equal(StatusOK))
+			Expect(rep.Findings[1].Status).To(Equal(StatusWarn))
+			Expect(rep.Findings[2].Status).To(Equal(StatusFail))
+			Expect(rep.Findings[3].Status).To(Equal(StatusFail))
+			Expect(rep.Findings[4].Status).To(Equal(StatusOK))
+			Expect(rep.Findings[4].Message).To(Equal("end-of-life date unknown"))
+
+			Expect(rep.Status()).To(Equal(StatusFail))
+			Expect(rep.Status().ExitCode()).To(Equal(2))
+		})
+
+		It("should only check release cycles of installed versions", func() {
+			withInstalled := []collector.ProductStatus{statuses[0]}
+			withInstalled[0].Installed = []string{"8.0.1", "7.0.15", "3.6.0"}
+
+			rep := Evaluate(withInstalled, thresholds, now)
+
+			Expect(rep.Findings).To(HaveLen(3))
+			Expect(rep.Findings[0].Message).To(ContainSubstring("3.6.0"))
+			Expect(rep.Findings[1].ReleaseCycle).To(Equal("8.0"))
+			Expect(rep.Findings[1].Installed).To(ConsistOf("8.0.1"))
+			Expect(rep.Findings[2].ReleaseCycle).To(Equal("7.0"))
+			Expect(rep.Status().ExitCode()).To(Equal(1))
+		})
+
+		It("should report fetch errors", func() {
+			rep := Evaluate([]collector.ProductStatus{
+				{Name: "redis", Installed: []string{"7.2.1"}, Err: errors.New("API returned non-OK status: 404")},
+			}, thresholds, now)
+
+			Expect(rep.Findings).To(HaveLen(1))
+			Expect(rep.Findings[0].Status).To(Equal(StatusError))
+			Expect(rep.Status().ExitCode()).To(Equal(2))
+		})
+
+		It("should exit with 0 when all releases are fine", func() {
+			rep := Evaluate([]collector.ProductStatus{
+				{Name: "mongo", Releases: statuses[0].Releases[:1]},
+			}, thresholds, now)
+
+			Expect(rep.Status().ExitCode()).To(Equal(0))
+		})
+	})
+
+	Context("When writing the report", func() {
+		rep := Evaluate(statuses, thresholds, now)
+
+		It("should write a table", func() {
+			var buf bytes.Buffer
+			Expect(rep.Write(&buf, "table")).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("STATUS"))
+			Expect(buf.String()).To(ContainSubstring("2 ok, 1 warn, 2 fail, 0 error"))
+		})
+
+		It("should write markdown", func() {
+			var buf bytes.Buffer
+			Expect(rep.Write(&buf, "markdown")).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("| --- |"))
+		})
+
+		It("should write json", func() {
+			var buf bytes.Buffer
+			Expect(rep.Write(&buf, "json")).To(Succeed())
+
+			var out map[string]any
+			Expect(json.Unmarshal(buf.Bytes(), &out)).To(Succeed())
+			Expect(out["warn"]).To(Equal("90d"))
+			Expect(out["status"]).To(Equal("fail"))
+			Expect(out["findings"]).To(HaveLen(5))
+		})
+
+		It("should write sarif with one result per warn or fail release", func() {
+			withLocation := rep
+			withLocation.ConfigFile = "./config.yml"
+			withLocation.ProductLines = map[string]int{"mongo": 3}
+
+			var buf bytes.Buffer
+			Expect(withLocation.Write(&buf, "sarif")).To(Succeed())
+
+			var log sarifLog
+			Expect(json.Unmarshal(buf.Bytes(), &log)).To(Succeed())
+			Expect(log.Version).To(Equal("2.1.0"))
+			Expect(log.Runs[0].Tool.Driver.Rules).To(HaveLen(3))
+
+			results := log.Runs[0].Results
+			Expect(results).To(HaveLen(3))
+			Expect(results[0].RuleID).To(Equal("endoflife/active"))
+			Expect(results[0].Level).To(Equal("warning"))
+			Expect(results[2].RuleID).To(Equal("endoflife/eol"))
+			Expect(results[2].Level).To(Equal("error"))
+			Expect(results[2].Locations[0].PhysicalLocation.ArtifactLocation).To(Equal(sarifArtifactLocation{URI: "config.yml", URIBaseID: "%SRCROOT%"}))
+			Expect(results[2].Locations[0].PhysicalLocation.Region.StartLine).To(Equal(3))
+		})
+
+		It("should point sarif results at the config file relative to the working directory", func() {
+			cwd, err := os.Getwd()
+			Expect(err).To(BeNil())
+
+			Expect(sarifArtifact(filepath.Join(cwd, "deploy", "config.yml"))).To(Equal(sarifArtifactLocation{URI: "deploy/config.yml", URIBaseID: "%SRCROOT%"}))
+			Expect(sarifArtifact(filepath.Join("deploy", "..", "config.yml"))).To(Equal(sarifArtifactLocation{URI: "config.yml", URIBaseID: "%SRCROOT%"}))
+
+			outside := filepath.Join(filepath.Dir(cwd), "other", "config.yml")
+			Expect(sarifArtifact(outside).URI).To(Equal("file://" + filepath.ToSlash(outside)))
+			Expect(sarifArtifact(outside).URIBaseID).To(BeEmpty())
+		})
+
+		It("should write junit with one testcase per release", func() {
+			var buf bytes.Buffer
+			Expect(rep.Write(&buf, "junit")).To(Succeed())
+
+			var suites junitTestSuites
+			Expect(xml.Unmarshal(buf.Bytes(), &suites)).To(Succeed())
+			Expect(suites.Tests).To(Equal(5))
+			Expect(suites.Failures).To(Equal(2))
+			Expect(suites.Suites).To(HaveLen(1))
+			Expect(suites.Suites[0].Cases[1].SystemOut).To(ContainSubstring("warning"))
+			Expect(suites.Suites[0].Cases[2].Failure).NotTo(BeNil())
+		})
+
+		It("should reject unknown formats", func() {
+			Expect(rep.Write(&bytes.Buffer{}, "xml")).NotTo(Succeed())
+		})
+	})
+
+	Context("When evaluating a scan", func() {
+		pinned := func(version, repo, file string) discovery.Installation {
+			return discovery.Installation{Source: "manifest", Product: "go", Version: version, Labels: map[string]string{"repo": repo, "file": file}}
+		}
+		scan := EvaluateScan([]collector.ProductStatus{
+			{
+				Name: "go",
+				Releases: []endoflife.ReleaseDetails{
+					{ReleaseCycleName: "1.22", EOLFrom: now.AddDate(1, 0, 0), LatestVersion: "1.22.5"},
+					{ReleaseCycleName: "1.20", EOLFrom: now.AddDate(-1, 0, 0), IsEol: true, LatestVersion: "1.20.14"},
+				},
+				Discovered: []discovery.Installation{
+					pinned("1.22.1", "web", "go.mod"),
+					pinned("1.20", "api", "go.mod"),
+					pinned("1.20", "api", "tools/go.mod"),
+					pinned("1.9", "legacy", "go.mod"),
+				},
+			},
+		}, thresholds, now)
+
+		It("should report a finding per pinned version", func() {
+			Expect(scan.Findings).To(HaveLen(4))
+			Expect(scan.Findings[0].Repo).To(Equal("api"))
+			Expect(scan.Findings[0].Status).To(Equal(StatusFail))
+			Expect(scan.Findings[2].Repo).To(Equal("legacy"))
+			Expect(scan.Findings[2].Status).To(Equal(StatusWarn))
+			Expect(scan.Findings[3].Status).To(Equal(StatusOK))
+
+			Expect(scan.Repos).To(Equal(3))
+			Expect(scan.EOLRepos()).To(Equal([]string{"api"}))
+			Expect(scan.Status().ExitCode()).To(Equal(2))
+		})
+
+		It("should write a table and json", func() {
+			var buf bytes.Buffer
+			Expect(scan.Write(&buf, "table")).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("tools/go.mod"))
+			Expect(buf.String()).To(ContainSubstring("1 of 3 repositories pin end-of-life versions"))
+
+			buf.Reset()
+			Expect(scan.Write(&buf, "json")).To(Succeed())
+			var out map[string]any
+			Expect(json.Unmarshal(buf.Bytes(), &out)).To(Succeed())
+			Expect(out["eol_repos"]).To(Equal([]any{"api"}))
+			Expect(out["findings"]).To(HaveLen(4))
+		})
+	})
+})
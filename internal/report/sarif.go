@@ -0,0 +1,216 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+	"github.com/veerendra2/gopackages/version"
+)
+
+const (
+	sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSrcRoot is the base of relative artifact URIs, the root of the
+	// checked out repository when run in CI.
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+// sarifRules has one rule per phase a release cycle can be reported in.
+var sarifRules = []sarifRule{
+	{
+		ID:               "endoflife/active",
+		Name:             "ActiveReleaseNearingEOL",
+		ShortDescription: sarifText{Text: "Release cycle is actively supported but reaches end-of-life soon"},
+		HelpURI:          "https://endoflife.date",
+	},
+	{
+		ID:               "endoflife/security",
+		Name:             "SecurityOnlyReleaseNearingEOL",
+		ShortDescription: sarifText{Text: "Release cycle only receives security fixes and reaches end-of-life soon"},
+		HelpURI:          "https://endoflife.date",
+	},
+	{
+		ID:               "endoflife/eol",
+		Name:             "EOLRelease",
+		ShortDescription: sarifText{Text: "Release cycle has reached end-of-life"},
+		HelpURI:          "https://endoflife.date",
+	},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	ShortDescription sarifText `json:"shortDescription"`
+	HelpURI          string    `json:"helpUri"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string    `json:"level"`
+	Message sarifText `json:"message"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifText       `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties map[string]any  `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSARIF renders the warn and fail findings as a SARIF 2.1.0 log, e.g. for
+// GitHub code scanning. Results point at the product in the config file.
+func (r Result) WriteSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "endoflife_exporter",
+			InformationURI: "https://github.com/veerendra2/endoflife_exporter",
+			Version:        version.Version,
+			Rules:          sarifRules,
+		}},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: true}},
+		Results:     []sarifResult{},
+	}
+	artifact := sarifArtifact(r.ConfigFile)
+
+	for _, f := range r.Findings {
+		if f.Status == StatusError || (f.Status == StatusWarn && f.ReleaseCycle == "") {
+			run.Invocations[0].ToolExecutionNotifications = append(run.Invocations[0].ToolExecutionNotifications, sarifNotification{
+				Level:   sarifLevel(f.Status),
+				Message: sarifText{Text: f.Product + ": " + f.Message},
+			})
+			if f.Status == StatusError {
+				run.Invocations[0].ExecutionSuccessful = false
+			}
+			continue
+		}
+		if f.Status != StatusWarn && f.Status != StatusFail {
+			continue
+		}
+
+		ruleIndex := sarifRuleIndex(f.Phase)
+		location := sarifPhysicalLocation{ArtifactLocation: artifact}
+		if line, ok := r.ProductLines[f.Product]; ok {
+			location.Region = &sarifRegion{StartLine: line}
+		}
+
+		properties := map[string]any{
+			"product":      f.Product,
+			"releaseCycle": f.ReleaseCycle,
+			"phase":        f.Phase,
+		}
+		if f.DaysToEOL != nil {
+			properties["eolFrom"] = f.EOLFrom
+			properties["daysToEol"] = *f.DaysToEOL
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:     sarifRules[ruleIndex].ID,
+			RuleIndex:  ruleIndex,
+			Level:      sarifLevel(f.Status),
+			Message:    sarifText{Text: f.Product + " " + f.ReleaseCycle + " " + f.Message},
+			Locations:  []sarifLocation{{PhysicalLocation: location}},
+			Properties: properties,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifArtifact returns the location of the config file relative to the
+// working directory, so that code scanning attaches the results to the file in
+// the repository. Files outside of it are referenced by an absolute file URI.
+func sarifArtifact(path string) sarifArtifactLocation {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return sarifArtifactLocation{URI: filepath.ToSlash(filepath.Clean(path))}
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return sarifArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: sarifSrcRoot}
+		}
+	}
+	uri := url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	if !strings.HasPrefix(uri.Path, "/") {
+		// Windows paths like C:/config.yml
+		uri.Path = "/" + uri.Path
+	}
+	return sarifArtifactLocation{URI: uri.String()}
+}
+
+func sarifRuleIndex(phase lifecycle.Phase) int {
+	switch phase {
+	case lifecycle.PhaseSecurity:
+		return 1
+	case lifecycle.PhaseEOL:
+		return 2
+	}
+	return 0
+}
+
+func sarifLevel(s Status) string {
+	if s == StatusWarn {
+		return "warning"
+	}
+	return "error"
+}
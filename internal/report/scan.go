@@ -0,0 +1,214 @@
+package report
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+)
+
+// ScanFinding is the check result of a version pinned in a file of a
+// repository.
+type ScanFinding struct {
+	Repo string `json:"repo"`
+	File string `json:"file"`
+	Finding
+}
+
+// ScanResult holds the findings of all pinned versions.
+type ScanResult struct {
+	GeneratedAt time.Time
+	Thresholds  Thresholds
+	Findings    []ScanFinding
+	// Repos is the number of scanned repositories with a pinned version
+	Repos int
+}
+
+// EvaluateScan checks the discovered versions of all products, which are
+// labeled with their repo and file by the manifest source, at now.
+func EvaluateScan(statuses []collector.ProductStatus, thresholds Thresholds, now time.Time) ScanResult {
+	rep := ScanResult{GeneratedAt: now, Thresholds: thresholds}
+	repos := map[string]bool{}
+
+	for _, status := range statuses {
+		for _, installation := range status.Discovered {
+			repos[installation.Labels["repo"]] = true
+
+			finding := ScanFinding{Repo: installation.Labels["repo"], File: installation.Labels["file"]}
+			rel, ok := lifecycle.MatchRelease(installation.Version, status.Releases)
+			switch {
+			case status.Err != nil && !ok:
+				finding.Finding = Finding{
+					Product:   status.Name,
+					Installed: []string{installation.Version},
+					Status:    StatusError,
+					Message:   status.Err.Error(),
+				}
+			case !ok:
+				finding.Finding = Finding{
+					Product:   status.Name,
+					Installed: []string{installation.Version},
+					Status:    StatusWarn,
+					Message:   fmt.Sprintf("no release cycle matches version %s", installation.Version),
+				}
+			default:
+				finding.Finding = evaluateRelease(status.Name, rel, []string{installation.Version}, thresholds, now)
+			}
+			rep.Findings = append(rep.Findings, finding)
+		}
+	}
+
+	slices.SortStableFunc(rep.Findings, func(a, b ScanFinding) int {
+		return cmp.Or(cmp.Compare(a.Repo, b.Repo), cmp.Compare(a.File, b.File), cmp.Compare(a.Product, b.Product))
+	})
+	rep.Repos = len(repos)
+
+	return rep
+}
+
+// result returns the findings without their location, to share the status
+// evaluation with the check result.
+func (r ScanResult) result() Result {
+	result := Result{GeneratedAt: r.GeneratedAt, Thresholds: r.Thresholds}
+	for _, f := range r.Findings {
+		result.Findings = append(result.Findings, f.Finding)
+	}
+	return result
+}
+
+// Status returns the most severe status of all findings.
+func (r ScanResult) Status() Status {
+	return r.result().Status()
+}
+
+// Count returns the number of findings with the given status.
+func (r ScanResult) Count(status Status) int {
+	return r.result().Count(status)
+}
+
+// EOLRepos returns the sorted repositories pinning a version that reached
+// end-of-life.
+func (r ScanResult) EOLRepos() []string {
+	repos := []string{}
+	for _, f := range r.Findings {
+		if f.Phase == lifecycle.PhaseEOL && !slices.Contains(repos, f.Repo) {
+			repos = append(repos, f.Repo)
+		}
+	}
+	slices.Sort(repos)
+	return repos
+}
+
+// Write renders the scan result in the given format.
+func (r ScanResult) Write(w io.Writer, format string) error {
+	switch format {
+	case "table":
+		return r.WriteTable(w)
+	case "json":
+		return r.WriteJSON(w)
+	case "markdown":
+		return r.WriteMarkdown(w)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+// WriteTable renders the findings as an aligned plain text table.
+func (r ScanResult) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "STATUS\tREPO\tFILE\tPRODUCT\tVERSION\tRELEASE\tEOL\tMESSAGE")
+	for _, f := range r.Findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			strings.ToUpper(string(f.Status)),
+			orDash(f.Repo),
+			f.File,
+			f.Product,
+			strings.Join(f.Installed, ", "),
+			orDash(f.ReleaseCycle),
+			orDash(f.EOLFrom),
+			f.Message,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(w, "\n"+r.summary())
+	return err
+}
+
+// WriteMarkdown renders the findings as a GitHub flavored markdown table.
+func (r ScanResult) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("## End-of-Life Scan\n\n")
+	b.WriteString("| Status | Repo | File | Product | Version | Release | EOL | Message |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "| %s %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			statusEmoji(f.Status),
+			f.Status,
+			escapeMarkdown(orDash(f.Repo)),
+			escapeMarkdown(f.File),
+			escapeMarkdown(f.Product),
+			escapeMarkdown(strings.Join(f.Installed, ", ")),
+			escapeMarkdown(orDash(f.ReleaseCycle)),
+			orDash(f.EOLFrom),
+			escapeMarkdown(f.Message),
+		)
+	}
+	b.WriteString("\n" + r.summary() + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type jsonScanResult struct {
+	GeneratedAt string         `json:"generated_at"`
+	Warn        string         `json:"warn"`
+	Fail        string         `json:"fail"`
+	Status      Status         `json:"status"`
+	Summary     map[Status]int `json:"summary"`
+	Repos       int            `json:"repos"`
+	EOLRepos    []string       `json:"eol_repos"`
+	Findings    []ScanFinding  `json:"findings"`
+}
+
+// WriteJSON renders the scan result as an indented JSON document.
+func (r ScanResult) WriteJSON(w io.Writer) error {
+	findings := r.Findings
+	if findings == nil {
+		findings = []ScanFinding{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonScanResult{
+		GeneratedAt: r.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Warn:        model.Duration(r.Thresholds.Warn).String(),
+		Fail:        model.Duration(r.Thresholds.Fail).String(),
+		Status:      r.Status(),
+		Summary: map[Status]int{
+			StatusOK:    r.Count(StatusOK),
+			StatusWarn:  r.Count(StatusWarn),
+			StatusFail:  r.Count(StatusFail),
+			StatusError: r.Count(StatusError),
+		},
+		Repos:    r.Repos,
+		EOLRepos: r.EOLRepos(),
+		Findings: findings,
+	})
+}
+
+func (r ScanResult) summary() string {
+	return fmt.Sprintf("%s\n%d of %d repositories pin end-of-life versions",
+		r.result().summary(), len(r.EOLRepos()), r.Repos)
+}
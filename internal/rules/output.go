@@ -0,0 +1,58 @@
+package rules
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PrometheusRule is the Kubernetes custom resource of the Prometheus Operator.
+type PrometheusRule struct {
+	APIVersion string     `yaml:"apiVersion"`
+	Kind       string     `yaml:"kind"`
+	Metadata   ObjectMeta `yaml:"metadata"`
+	Spec       RuleFile   `yaml:"spec"`
+}
+
+type ObjectMeta struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+// Marshal validates the rules and renders them as YAML, either as plain rule
+// file ("plain") or wrapped in a PrometheusRule resource ("crd").
+func (f RuleFile) Marshal(format string, meta ObjectMeta) ([]byte, error) {
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("generated rules are invalid: %w", err)
+	}
+
+	var v any
+	switch format {
+	case "plain":
+		v = f
+	case "crd":
+		v = PrometheusRule{
+			APIVersion: "monitoring.coreos.com/v1",
+			Kind:       "PrometheusRule",
+			Metadata:   meta,
+			Spec:       f,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
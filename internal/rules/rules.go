@@ -0,0 +1,204 @@
+// Package rules generates Prometheus alerting rules for the metrics of the
+// collector from the alerting thresholds in the configuration.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+)
+
+const groupName = "endoflife.rules"
+
+type Rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type Group struct {
+	Name  string `yaml:"name"`
+	Rules []Rule `yaml:"rules"`
+}
+
+// RuleFile is the content of a Prometheus rule file and of the spec of a
+// PrometheusRule resource.
+type RuleFile struct {
+	Groups []Group `yaml:"groups"`
+}
+
+// scope is a set of products sharing the same alerting thresholds.
+type scope struct {
+	selector         string // Label matchers on product_name, empty for all products
+	eolReachedFilter string // Like selector, but also excluding products with all_releases
+	skipEOLReached   bool
+	alerting         config.Alerting
+}
+
+// Generate builds the alerting rules. Products with their own alerting
+// thresholds get dedicated rules, the global rules exclude them.
+func Generate(cfg config.Config) RuleFile {
+	var overridden, allReleases []string
+	var scopes []scope
+
+	for _, product := range cfg.Products {
+		if product.AllReleases {
+			allReleases = append(allReleases, product.Name)
+		}
+		if product.Alerting == nil {
+			continue
+		}
+		overridden = append(overridden, product.Name)
+		sel := "product_name=" + strconv.Quote(product.Name)
+		scopes = append(scopes, scope{
+			selector:         sel,
+			eolReachedFilter: sel,
+			skipEOLReached:   product.AllReleases,
+			alerting:         product.Alerting.Merge(cfg.Alerting),
+		})
+	}
+
+	global := scope{
+		selector:         excludeMatcher(overridden),
+		eolReachedFilter: excludeMatcher(append(slices.Clone(overridden), allReleases...)),
+		alerting:         cfg.Alerting,
+	}
+	scopes = append([]scope{global}, scopes...)
+
+	group := Group{Name: groupName}
+	for _, s := range scopes {
+		group.Rules = append(group.Rules, s.rules()...)
+	}
+
+	return RuleFile{Groups: []Group{group}}
+}
+
+func (s scope) rules() []Rule {
+	eol := collector.EolFromTimestampSecondsMetric.Name
+	warn := seconds(time.Duration(s.alerting.Warn))
+	critical := seconds(time.Duration(s.alerting.Critical))
+
+	rules := []Rule{
+		{
+			Alert: "EndOfLifeReleaseEOLSoon",
+			Expr:  fmt.Sprintf("(%s{%s} - time()) < %d >= %d", eol, s.selector, warn, critical),
+			For:   "1h",
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary":     "Product '{{ $labels.product_name }}' release cycle '{{ $labels.release_cycle_name }}' reaches its End-of-Life soon",
+				"description": "Product '{{ $labels.product_name }}' release cycle '{{ $labels.release_cycle_name }}' reaches its End-of-Life in {{ $value | humanizeDuration }}.",
+			},
+		},
+		{
+			Alert: "EndOfLifeReleaseEOLCritical",
+			Expr:  fmt.Sprintf("(%s{%s} - time()) < %d >= 0", eol, s.selector, critical),
+			For:   "1h",
+			Labels: map[string]string{
+				"severity": "critical",
+			},
+			Annotations: map[string]string{
+				"summary":     "Product '{{ $labels.product_name }}' release cycle '{{ $labels.release_cycle_name }}' reaches its End-of-Life very soon",
+				"description": "Product '{{ $labels.product_name }}' release cycle '{{ $labels.release_cycle_name }}' reaches its End-of-Life in {{ $value | humanizeDuration }}.",
+			},
+		},
+	}
+
+	// Products tracking all release cycles would alert on every historic cycle
+	if !s.skipEOLReached {
+		rules = append(rules, Rule{
+			Alert: "EndOfLifeReleaseEOLReached",
+			Expr:  fmt.Sprintf(`%s{%s} == 1`, collector.ProductInfoMetric.Name, joinMatchers(`is_eol="true"`, s.eolReachedFilter)),
+			For:   "1h",
+			Labels: map[string]string{
+				"severity": "critical",
+			},
+			Annotations: map[string]string{
+				"summary":     "Product '{{ $labels.product_name }}' release cycle '{{ $labels.release_cycle_name }}' reached its End-of-Life",
+				"description": "Product '{{ $labels.product_name }}' release cycle '{{ $labels.release_cycle_name }}' reached its End-of-Life and no longer receives any support.",
+			},
+		})
+	}
+
+	if s.alerting.EOAS != nil && *s.alerting.EOAS {
+		rules = append(rules, Rule{
+			Alert: "EndOfLifeReleaseActiveSupportEndingSoon",
+			Expr:  fmt.Sprintf("(%s{%s} - time()) < %d >= 0", collector.EoasFromTimestampSecondsMetric.Name, s.selector, warn),
+			For:   "1h",
+			Labels: map[string]string{
+				"severity": "info",
+			},
+			Annotations: map[string]string{
+				"summary":     "Active support of product '{{ $labels.product_name }}' release cycle '{{ $labels.release_cycle_name }}' ends soon",
+				"description": "Product '{{ $labels.product_name }}' release cycle '{{ $labels.release_cycle_name }}' only receives security fixes in {{ $value | humanizeDuration }}.",
+			},
+		})
+	}
+
+	if s.alerting.Outdated != nil && *s.alerting.Outdated {
+		rules = append(rules, Rule{
+			Alert: "EndOfLifeInstalledVersionOutdated",
+			Expr:  fmt.Sprintf(`%s{%s} == 1`, collector.InstalledVersionInfoMetric.Name, joinMatchers(`is_outdated="true"`, s.selector)),
+			For:   "1h",
+			Labels: map[string]string{
+				"severity": "info",
+			},
+			Annotations: map[string]string{
+				"summary":     "Installed version {{ $labels.installed_version }} of product '{{ $labels.product_name }}' is outdated",
+				"description": "Product '{{ $labels.product_name }}' version {{ $labels.installed_version }} is installed, the latest version of release cycle '{{ $labels.release_cycle_name }}' is {{ $labels.latest_version }}.",
+			},
+		})
+	}
+
+	if s.alerting.FetchFailure != nil && *s.alerting.FetchFailure {
+		rules = append(rules, Rule{
+			Alert: "EndOfLifeProductFetchFailed",
+			Expr:  fmt.Sprintf("%s{%s} == 0", collector.ProductFetchSuccessMetric.Name, s.selector),
+			For:   "1h",
+			Labels: map[string]string{
+				"severity": "warning",
+			},
+			Annotations: map[string]string{
+				"summary":     "Failed to fetch product '{{ $labels.product_name }}' from endoflife.date",
+				"description": "The exporter could not fetch product '{{ $labels.product_name }}' from the endoflife.date API, its EOL data may be stale or missing.",
+			},
+		})
+	}
+
+	return rules
+}
+
+// excludeMatcher returns a label matcher excluding the given products.
+func excludeMatcher(products []string) string {
+	if len(products) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(products))
+	for i, p := range products {
+		quoted[i] = regexp.QuoteMeta(p)
+	}
+	return "product_name!~" + strconv.Quote(strings.Join(quoted, "|"))
+}
+
+func joinMatchers(matchers ...string) string {
+	var nonEmpty []string
+	for _, m := range matchers {
+		if m != "" {
+			nonEmpty = append(nonEmpty, m)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
@@ -0,0 +1,134 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRules(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rules Suite")
+}
+
+func alerts(group Group, name string) []Rule {
+	var found []Rule
+	for _, r := range group.Rules {
+		if r.Alert == name {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+var _ = Describe("Rules Suite", func() {
+	enabled, disabled := true, false
+	cfg := config.Config{
+		Alerting: config.Alerting{
+			Warn:         model.Duration(90 * 24 * time.Hour),
+			Critical:     model.Duration(30 * 24 * time.Hour),
+			EOAS:         &enabled,
+			Outdated:     &enabled,
+			FetchFailure: &enabled,
+		},
+		Products: []config.Product{
+			{Name: "mongo", Alerting: &config.Alerting{Critical: model.Duration(60 * 24 * time.Hour), EOAS: &disabled}},
+			{Name: "ubuntu", AllReleases: true},
+			{Name: "redis"},
+		},
+	}
+
+	Context("When generating rules", func() {
+		file := Generate(cfg)
+
+		It("should produce valid rules", func() {
+			Expect(file.Validate()).To(Succeed())
+			Expect(file.Groups).To(HaveLen(1))
+		})
+
+		It("should exclude products with own thresholds from the global rules", func() {
+			soon := alerts(file.Groups[0], "EndOfLifeReleaseEOLSoon")
+			Expect(soon).To(HaveLen(2))
+			Expect(soon[0].Expr).To(Equal(`(endoflife_eol_from_timestamp_seconds{product_name!~"mongo"} - time()) < 7776000 >= 2592000`))
+			Expect(soon[1].Expr).To(Equal(`(endoflife_eol_from_timestamp_seconds{product_name="mongo"} - time()) < 7776000 >= 5184000`))
+		})
+
+		It("should not alert on reached EOL for products tracking all releases", func() {
+			reached := alerts(file.Groups[0], "EndOfLifeReleaseEOLReached")
+			Expect(reached).To(HaveLen(2))
+			Expect(reached[0].Expr).To(ContainSubstring(`product_name!~"mongo|ubuntu"`))
+		})
+
+		It("should respect disabled alerts per product", func() {
+			eoas := alerts(file.Groups[0], "EndOfLifeReleaseActiveSupportEndingSoon")
+			Expect(eoas).To(HaveLen(1))
+		})
+
+		It("should scope fetch failure alerts per product", func() {
+			cfg := config.Config{
+				Alerting: config.Alerting{
+					Warn:         model.Duration(90 * 24 * time.Hour),
+					Critical:     model.Duration(30 * 24 * time.Hour),
+					FetchFailure: &enabled,
+				},
+				Products: []config.Product{
+					{Name: "mongo", Alerting: &config.Alerting{FetchFailure: &disabled}},
+					{Name: "redis"},
+				},
+			}
+
+			failed := alerts(Generate(cfg).Groups[0], "EndOfLifeProductFetchFailed")
+			Expect(failed).To(HaveLen(1))
+			Expect(failed[0].Expr).To(Equal(`endoflife_product_fetch_success{product_name!~"mongo"} == 0`))
+
+			cfg.Alerting.FetchFailure = &disabled
+			cfg.Products[0].Alerting.FetchFailure = &enabled
+			failed = alerts(Generate(cfg).Groups[0], "EndOfLifeProductFetchFailed")
+			Expect(failed).To(HaveLen(1))
+			Expect(failed[0].Expr).To(Equal(`endoflife_product_fetch_success{product_name="mongo"} == 0`))
+		})
+	})
+
+	Context("When validating rules", func() {
+		It("should reject unknown metrics and labels", func() {
+			file := RuleFile{Groups: []Group{{Name: "test", Rules: []Rule{
+				{Alert: "Unknown", Expr: "endoflife_unknown > 0", Labels: map[string]string{"severity": "info"}},
+				{Alert: "BadLabel", Expr: `endoflife_product_info{team="a"} == 1`, Labels: map[string]string{"severity": "info"}},
+				{Alert: "Bad Name", Expr: "(endoflife_product_info", For: "1x"},
+			}}}}
+
+			err := file.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`unknown metric "endoflife_unknown"`))
+			Expect(err.Error()).To(ContainSubstring(`has no label "team"`))
+			Expect(err.Error()).To(ContainSubstring("invalid alert name"))
+			Expect(err.Error()).To(ContainSubstring("unbalanced parentheses"))
+			Expect(err.Error()).To(ContainSubstring("missing severity label"))
+		})
+	})
+
+	Context("When marshalling rules", func() {
+		It("should wrap rules in a PrometheusRule", func() {
+			out, err := Generate(cfg).Marshal("crd", ObjectMeta{Name: "endoflife", Namespace: "monitoring"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var rule PrometheusRule
+			Expect(yaml.Unmarshal(out, &rule)).To(Succeed())
+			Expect(rule.Kind).To(Equal("PrometheusRule"))
+			Expect(rule.Metadata.Namespace).To(Equal("monitoring"))
+			Expect(rule.Spec.Groups[0].Rules).NotTo(BeEmpty())
+		})
+
+		It("should render a plain rule file", func() {
+			out, err := Generate(cfg).Marshal("plain", ObjectMeta{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.HasPrefix(string(out), "---\ngroups:\n")).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+)
+
+var (
+	alertNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	// selectorRe matches a metric name of the exporter and its optional label matchers
+	selectorRe = regexp.MustCompile(`\b(endoflife_[a-z_]+)(\{([^}]*)\})?`)
+	matcherRe  = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*"(?:[^"\\]|\\.)*"\s*$`)
+)
+
+// Validate checks the rules for invalid names, durations and label matchers,
+// and makes sure every referenced metric and label is exported by the collector.
+func (f RuleFile) Validate() error {
+	var errs []error
+
+	for _, group := range f.Groups {
+		if group.Name == "" {
+			errs = append(errs, errors.New("group name must not be empty"))
+		}
+		for _, rule := range group.Rules {
+			if err := rule.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("group %q: rule %q: %w", group.Name, rule.Alert, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r Rule) validate() error {
+	var errs []error
+
+	if !alertNameRe.MatchString(r.Alert) {
+		errs = append(errs, fmt.Errorf("invalid alert name"))
+	}
+	if r.For != "" {
+		if _, err := model.ParseDuration(r.For); err != nil {
+			errs = append(errs, fmt.Errorf("invalid for duration: %w", err))
+		}
+	}
+	for name := range r.Labels {
+		if !model.LabelName(name).IsValid() {
+			errs = append(errs, fmt.Errorf("invalid label name %q", name))
+		}
+	}
+	if _, ok := r.Labels["severity"]; !ok {
+		errs = append(errs, errors.New("missing severity label"))
+	}
+	if err := validateExpr(r.Expr); err != nil {
+		errs = append(errs, fmt.Errorf("invalid expression %q: %w", r.Expr, err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateExpr(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return errors.New("expression must not be empty")
+	}
+
+	depth := 0
+	for _, c := range expr {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth < 0 {
+			return errors.New("unbalanced parentheses")
+		}
+	}
+	if depth != 0 {
+		return errors.New("unbalanced parentheses")
+	}
+
+	selectors := selectorRe.FindAllStringSubmatch(expr, -1)
+	if len(selectors) == 0 {
+		return errors.New("expression does not reference any exporter metric")
+	}
+
+	for _, sel := range selectors {
+		idx := slices.IndexFunc(collector.Metrics, func(m collector.Metric) bool { return m.Name == sel[1] })
+		if idx < 0 {
+			return fmt.Errorf("unknown metric %q", sel[1])
+		}
+		if strings.TrimSpace(sel[3]) == "" {
+			continue
+		}
+		for _, matcher := range strings.Split(sel[3], ",") {
+			m := matcherRe.FindStringSubmatch(matcher)
+			if m == nil {
+				return fmt.Errorf("invalid label matcher %q", strings.TrimSpace(matcher))
+			}
+			if !slices.Contains(collector.Metrics[idx].Labels, m[1]) {
+				return fmt.Errorf("metric %q has no label %q", sel[1], m[1])
+			}
+		}
+	}
+
+	return nil
+}
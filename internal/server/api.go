@@ -0,0 +1,308 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/history"
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+const dateLayout = "2006-01-02"
+
+// installedView is an installed version, "config" for the installed versions
+// of the configuration, or the discovery source with the labels of the
+// installation.
+type installedView struct {
+	Version string            `json:"version"`
+	Status  string            `json:"status"`
+	Source  string            `json:"source"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type releaseView struct {
+	Name              string          `json:"name"`
+	Phase             string          `json:"phase"`
+	IsEol             bool            `json:"is_eol"`
+	IsEoas            bool            `json:"is_eoas"`
+	IsLts             bool            `json:"is_lts"`
+	IsMaintained      bool            `json:"is_maintained"`
+	ReleaseDate       string          `json:"release_date"`
+	EOASFrom          string          `json:"eoas_from,omitempty"`
+	EOLFrom           string          `json:"eol_from,omitempty"`
+	DaysToEOL         *int            `json:"days_to_eol"`
+	LatestVersion     string          `json:"latest_version"`
+	LatestVersionDate string          `json:"latest_version_date,omitempty"`
+	LatestVersionLink string          `json:"latest_version_link,omitempty"`
+	Installed         []installedView `json:"installed,omitempty"`
+}
+
+type productView struct {
+	Name               string        `json:"name"`
+	Label              string        `json:"label,omitempty"`
+	Link               string        `json:"link,omitempty"`
+	LastSuccess        *time.Time    `json:"last_success,omitempty"`
+	Error              string        `json:"error,omitempty"`
+	UnmatchedInstalled []string      `json:"unmatched_installed,omitempty"`
+	Releases           []releaseView `json:"releases"`
+}
+
+type productListResponse struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Total       int           `json:"total"`
+	Result      []productView `json:"result"`
+}
+
+type releaseListResponse struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Total       int           `json:"total"`
+	Result      []releaseView `json:"result"`
+}
+
+type historyChangeView struct {
+	Product      string `json:"product"`
+	ReleaseCycle string `json:"release_cycle"`
+	Date         string `json:"date"`
+	Old          string `json:"old"`
+	New          string `json:"new"`
+	ObservedAt   string `json:"observed_at"`
+}
+
+type historyListResponse struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Total       int                 `json:"total"`
+	Result      []historyChangeView `json:"result"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// releaseFilter selects release cycles by the "status" and "within" query
+// parameters. The status is evaluated at now + within, so status=eol&within=90d
+// selects cycles that are EOL or reach EOL within 90 days. Without status,
+// within selects cycles whose EOL date is known and before now + within.
+type releaseFilter struct {
+	phases []lifecycle.Phase
+	within time.Duration
+	hasWin bool
+}
+
+func parseReleaseFilter(r *http.Request) (releaseFilter, error) {
+	filter := releaseFilter{}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		for _, s := range strings.Split(status, ",") {
+			phase := lifecycle.Phase(strings.ToLower(strings.TrimSpace(s)))
+			if !slices.Contains([]lifecycle.Phase{lifecycle.PhaseActive, lifecycle.PhaseSecurity, lifecycle.PhaseEOL}, phase) {
+				return filter, fmt.Errorf("invalid status %q, must be one of active, security or eol", s)
+			}
+			filter.phases = append(filter.phases, phase)
+		}
+	}
+
+	if within := r.URL.Query().Get("within"); within != "" {
+		d, err := model.ParseDuration(within)
+		if err != nil {
+			return filter, fmt.Errorf("invalid within %q: %w", within, err)
+		}
+		filter.within = time.Duration(d)
+		filter.hasWin = true
+	}
+
+	return filter, nil
+}
+
+func (f releaseFilter) match(rel endoflife.ReleaseDetails, now time.Time) bool {
+	at := now.Add(f.within)
+	if len(f.phases) > 0 {
+		return slices.Contains(f.phases, lifecycle.PhaseAt(rel, at))
+	}
+	if f.hasWin {
+		return lifecycle.HasEOLDate(rel) && !rel.EOLFrom.After(at)
+	}
+	return true
+}
+
+func (f releaseFilter) active() bool {
+	return len(f.phases) > 0 || f.hasWin
+}
+
+// APIHandler serves the cached release data as JSON.
+//
+//	GET /api/v1/products
+//	GET /api/v1/products/{name}/releases
+//	GET /api/v1/history
+func APIHandler(exporter *collector.Exporter) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v1/products", func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseReleaseFilter(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+			return
+		}
+
+		now := time.Now()
+		resp := productListResponse{GeneratedAt: now, Result: []productView{}}
+		for _, status := range exporter.Products() {
+			view := newProductView(status, filter, now)
+			if filter.active() && len(view.Releases) == 0 {
+				continue
+			}
+			resp.Result = append(resp.Result, view)
+		}
+		resp.Total = len(resp.Result)
+
+		writeJSON(w, http.StatusOK, resp)
+	})
+
+	mux.HandleFunc("GET /api/v1/products/{name}/releases", func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseReleaseFilter(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+			return
+		}
+
+		name := r.PathValue("name")
+		statuses := exporter.Products()
+		idx := slices.IndexFunc(statuses, func(s collector.ProductStatus) bool { return s.Name == name })
+		if idx < 0 {
+			writeJSON(w, http.StatusNotFound, apiError{Error: fmt.Sprintf("product %q is not tracked", name)})
+			return
+		}
+
+		now := time.Now()
+		view := newProductView(statuses[idx], filter, now)
+		writeJSON(w, http.StatusOK, releaseListResponse{
+			GeneratedAt: now,
+			Total:       len(view.Releases),
+			Result:      view.Releases,
+		})
+	})
+
+	mux.HandleFunc("GET /api/v1/history", func(w http.ResponseWriter, r *http.Request) {
+		product := r.URL.Query().Get("product")
+		release := r.URL.Query().Get("release")
+		dateType := r.URL.Query().Get("date")
+		if dateType != "" && !slices.Contains(history.DateTypes, history.DateType(dateType)) {
+			writeJSON(w, http.StatusBadRequest, apiError{Error: fmt.Sprintf("invalid date %q, must be one of eol, eoas or eoes", dateType)})
+			return
+		}
+
+		resp := historyListResponse{GeneratedAt: time.Now(), Result: []historyChangeView{}}
+		for _, c := range exporter.DateChanges() {
+			if (product != "" && c.Product != product) ||
+				(release != "" && c.ReleaseCycle != release) ||
+				(dateType != "" && string(c.Date) != dateType) {
+				continue
+			}
+			resp.Result = append(resp.Result, newHistoryChangeView(c))
+		}
+		resp.Total = len(resp.Result)
+
+		writeJSON(w, http.StatusOK, resp)
+	})
+
+	return mux
+}
+
+func newHistoryChangeView(c history.Change) historyChangeView {
+	return historyChangeView{
+		Product:      c.Product,
+		ReleaseCycle: c.ReleaseCycle,
+		Date:         string(c.Date),
+		Old:          formatHistoryDate(c.Old),
+		New:          formatHistoryDate(c.New),
+		ObservedAt:   c.ObservedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// formatHistoryDate returns "" for unset and "unknown" for unknown dates.
+func formatHistoryDate(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case t.Equal(endoflife.UnknownDate):
+		return "unknown"
+	}
+	return t.Format(dateLayout)
+}
+
+func newProductView(status collector.ProductStatus, filter releaseFilter, now time.Time) productView {
+	view := productView{
+		Name:     status.Name,
+		Label:    status.Label,
+		Link:     status.Link,
+		Releases: []releaseView{},
+	}
+	if !status.LastSuccess.IsZero() {
+		view.LastSuccess = &status.LastSuccess
+	}
+	if status.Err != nil {
+		view.Error = status.Err.Error()
+	}
+
+	all := make([]installedView, 0, len(status.Installed)+len(status.Discovered))
+	for _, version := range status.Installed {
+		all = append(all, installedView{Version: version, Source: "config"})
+	}
+	for _, installation := range status.Discovered {
+		all = append(all, installedView{Version: installation.Version, Source: installation.Source, Labels: installation.Labels})
+	}
+
+	installed := make(map[string][]installedView)
+	for _, iv := range all {
+		rel, ok := lifecycle.MatchRelease(iv.Version, status.Releases)
+		if !ok {
+			if !slices.Contains(view.UnmatchedInstalled, iv.Version) {
+				view.UnmatchedInstalled = append(view.UnmatchedInstalled, iv.Version)
+			}
+			continue
+		}
+		iv.Status = string(lifecycle.GetInstalledStatus(iv.Version, rel))
+		installed[rel.ReleaseCycleName] = append(installed[rel.ReleaseCycleName], iv)
+	}
+
+	for _, rel := range status.Releases {
+		if !filter.match(rel, now) {
+			continue
+		}
+		rv := newReleaseView(rel, now)
+		rv.Installed = installed[rel.ReleaseCycleName]
+		view.Releases = append(view.Releases, rv)
+	}
+
+	return view
+}
+
+func newReleaseView(rel endoflife.ReleaseDetails, now time.Time) releaseView {
+	rv := releaseView{
+		Name:              rel.ReleaseCycleName,
+		Phase:             string(lifecycle.PhaseAt(rel, now)),
+		IsEol:             rel.IsEol,
+		IsEoas:            rel.IsEoas,
+		IsLts:             rel.IsLts,
+		IsMaintained:      rel.IsMaintained,
+		ReleaseDate:       rel.ReleaseCycleDate.Format(dateLayout),
+		LatestVersion:     rel.LatestVersion,
+		LatestVersionLink: rel.LatestVersionLink,
+	}
+	if !rel.EOASFrom.IsZero() {
+		rv.EOASFrom = rel.EOASFrom.Format(dateLayout)
+	}
+	if days, ok := lifecycle.DaysToEOL(rel, now); ok {
+		rv.EOLFrom = rel.EOLFrom.Format(dateLayout)
+		rv.DaysToEOL = &days
+	}
+	if !rel.LatestVersionDate.Equal(endoflife.UnknownDate) {
+		rv.LatestVersionDate = rel.LatestVersionDate.Format(dateLayout)
+	}
+	return rv
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+var _ = Describe("API", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	It("should include configured and discovered installed versions", func() {
+		status := collector.ProductStatus{
+			Name:      "postgresql",
+			Installed: []string{"16.1"},
+			Discovered: []discovery.Installation{
+				{Source: "kubernetes", Product: "postgresql", Version: "15.4", Labels: map[string]string{"namespace": "shop", "workload": "statefulset/db"}},
+				{Source: "kubernetes", Product: "postgresql", Version: "9.6.24", Labels: map[string]string{"namespace": "legacy", "workload": "pod/db"}},
+			},
+			Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "16", EOLFrom: now.AddDate(4, 0, 0), LatestVersion: "16.1"},
+				{ReleaseCycleName: "15", EOLFrom: now.AddDate(3, 0, 0), LatestVersion: "15.5"},
+			},
+		}
+
+		view := newProductView(status, releaseFilter{}, now)
+
+		Expect(view.Releases).To(HaveLen(2))
+		Expect(view.Releases[0].Installed).To(Equal([]installedView{{Version: "16.1", Status: "current", Source: "config"}}))
+		Expect(view.Releases[1].Installed).To(Equal([]installedView{
+			{Version: "15.4", Status: "outdated", Source: "kubernetes", Labels: map[string]string{"namespace": "shop", "workload": "statefulset/db"}},
+		}))
+		Expect(view.UnmatchedInstalled).To(Equal([]string{"9.6.24"}))
+	})
+
+	Context("When filtering releases", func() {
+		var handler http.Handler
+
+		BeforeEach(func() {
+			exporter, err := collector.NewExporter(config.Config{Products: []config.Product{
+				{Name: "mongo", AllReleases: true},
+				{Name: "redis", AllReleases: true},
+			}})
+			Expect(err).To(BeNil())
+
+			today := time.Now().UTC().Truncate(24 * time.Hour)
+			exporter.Restore(collector.Snapshot{Products: map[string]collector.ProductSnapshot{
+				"mongo": {LastSuccess: today, Releases: []endoflife.ReleaseDetails{
+					{ReleaseCycleName: "7.0", EOASFrom: today.AddDate(1, 0, 0), EOLFrom: today.AddDate(2, 0, 0)},
+					{ReleaseCycleName: "6.0", EOASFrom: today.AddDate(0, 0, -10), EOLFrom: today.AddDate(0, 0, 60)},
+					{ReleaseCycleName: "5.0", EOLFrom: today.AddDate(0, 0, -30), IsEol: true},
+				}},
+				"redis": {LastSuccess: today, Releases: []endoflife.ReleaseDetails{
+					{ReleaseCycleName: "7.2", EOLFrom: endoflife.UnknownDate},
+					{ReleaseCycleName: "6.2", EOLFrom: today.AddDate(0, 0, 400)},
+				}},
+			}})
+			handler = APIHandler(exporter)
+		})
+
+		get := func(path string, body any) int {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			Expect(rec.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+			Expect(json.Unmarshal(rec.Body.Bytes(), body)).To(Succeed())
+			return rec.Code
+		}
+
+		releases := func(path string) map[string][]string {
+			var resp productListResponse
+			Expect(get(path, &resp)).To(Equal(http.StatusOK))
+			Expect(resp.Total).To(Equal(len(resp.Result)))
+
+			names := map[string][]string{}
+			for _, product := range resp.Result {
+				names[product.Name] = []string{}
+				for _, rel := range product.Releases {
+					names[product.Name] = append(names[product.Name], rel.Name)
+				}
+			}
+			return names
+		}
+
+		It("should list all products and releases without filters", func() {
+			Expect(releases("/api/v1/products")).To(Equal(map[string][]string{
+				"mongo": {"7.0", "6.0", "5.0"},
+				"redis": {"7.2", "6.2"},
+			}))
+		})
+
+		It("should evaluate the status at now plus within", func() {
+			Expect(releases("/api/v1/products?status=eol")).To(Equal(map[string][]string{"mongo": {"5.0"}}))
+			Expect(releases("/api/v1/products?status=eol&within=90d")).To(Equal(map[string][]string{"mongo": {"6.0", "5.0"}}))
+		})
+
+		It("should accept comma separated statuses", func() {
+			Expect(releases("/api/v1/products?status=active,security")).To(Equal(map[string][]string{
+				"mongo": {"7.0", "6.0"},
+				"redis": {"7.2", "6.2"},
+			}))
+		})
+
+		It("should only select releases with a known EOL date by within", func() {
+			Expect(releases("/api/v1/products?within=90d")).To(Equal(map[string][]string{"mongo": {"6.0", "5.0"}}))
+
+			var resp releaseListResponse
+			Expect(get("/api/v1/products/redis/releases?within=100y", &resp)).To(Equal(http.StatusOK))
+			Expect(resp.Total).To(Equal(1))
+			Expect(resp.Result[0].Name).To(Equal("6.2"))
+		})
+
+		It("should reject invalid filters", func() {
+			for _, path := range []string{
+				"/api/v1/products?status=supported",
+				"/api/v1/products?within=soon",
+				"/api/v1/products/mongo/releases?status=eol,unknown",
+			} {
+				var resp apiError
+				Expect(get(path, &resp)).To(Equal(http.StatusBadRequest), path)
+				Expect(resp.Error).NotTo(BeEmpty())
+			}
+		})
+
+		It("should not find untracked products", func() {
+			var resp apiError
+			Expect(get("/api/v1/products/nginx/releases", &resp)).To(Equal(http.StatusNotFound))
+			Expect(resp.Error).To(ContainSubstring("nginx"))
+		})
+	})
+})
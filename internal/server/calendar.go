@@ -0,0 +1,205 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+const (
+	calendarDateLayout  = "20060102"
+	calendarStampLayout = "20060102T150405Z"
+	calendarLabelPrefix = "label."
+)
+
+// calendarFilter selects products by the "product" and "label.<name>" query
+// parameters. Both accept comma separated values and can be repeated.
+type calendarFilter struct {
+	products []string
+	labels   map[string][]string
+	alarms   []int
+	lookback int // Days before today from which past events are included
+}
+
+func parseCalendarFilter(r *http.Request) (calendarFilter, error) {
+	filter := calendarFilter{labels: make(map[string][]string)}
+
+	for key, values := range r.URL.Query() {
+		switch {
+		case key == "product":
+			filter.products = splitValues(values)
+		case key == "alarm":
+			for _, v := range splitValues(values) {
+				days, err := strconv.Atoi(v)
+				if err != nil || days < 0 {
+					return filter, fmt.Errorf("invalid alarm %q, must be a number of days", v)
+				}
+				filter.alarms = append(filter.alarms, days)
+			}
+		case key == "lookback":
+			days, err := strconv.Atoi(r.URL.Query().Get(key))
+			if err != nil || days < 0 {
+				return filter, fmt.Errorf("invalid lookback %q, must be a number of days", r.URL.Query().Get(key))
+			}
+			filter.lookback = days
+		case strings.HasPrefix(key, calendarLabelPrefix):
+			filter.labels[strings.TrimPrefix(key, calendarLabelPrefix)] = splitValues(values)
+		}
+	}
+
+	return filter, nil
+}
+
+func (f calendarFilter) match(status collector.ProductStatus) bool {
+	if len(f.products) > 0 && !slices.Contains(f.products, status.Name) {
+		return false
+	}
+	for name, values := range f.labels {
+		if !slices.Contains(values, status.Labels[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func splitValues(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// CalendarHandler serves the upcoming EOL, EOAS and EOES dates of the tracked
+// release cycles as all-day events in iCalendar format. The "alarm" query
+// parameter adds reminders the given number of days before each event, the
+// "lookback" parameter includes past events of the given number of days.
+func CalendarHandler(exporter *collector.Exporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseCalendarFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="endoflife.ics"`)
+		if _, err := w.Write([]byte(newCalendar(exporter.Products(), filter, time.Now()))); err != nil {
+			slog.Warn("Failed to write calendar", "error", err)
+		}
+	})
+}
+
+type calendarEvent struct {
+	kind    string // eol, eoas or eoes
+	summary string
+	date    time.Time
+}
+
+func releaseEvents(rel endoflife.ReleaseDetails) []calendarEvent {
+	var events []calendarEvent
+	if !rel.EOASFrom.IsZero() {
+		events = append(events, calendarEvent{kind: "eoas", summary: "end of active support", date: rel.EOASFrom})
+	}
+	if lifecycle.HasEOLDate(rel) {
+		events = append(events, calendarEvent{kind: "eol", summary: "end-of-life", date: rel.EOLFrom})
+	}
+	if !rel.EOESFrom.IsZero() {
+		events = append(events, calendarEvent{kind: "eoes", summary: "end of extended support", date: rel.EOESFrom})
+	}
+	return events
+}
+
+func newCalendar(statuses []collector.ProductStatus, filter calendarFilter, now time.Time) string {
+	var b strings.Builder
+
+	writeCalendarLine(&b, "BEGIN:VCALENDAR")
+	writeCalendarLine(&b, "VERSION:2.0")
+	writeCalendarLine(&b, "PRODID:-//endoflife_exporter//EN")
+	writeCalendarLine(&b, "CALSCALE:GREGORIAN")
+	writeCalendarLine(&b, "METHOD:PUBLISH")
+	writeCalendarLine(&b, "X-WR-CALNAME:End-of-Life")
+
+	// Event dates are days in UTC, so an event of today is still upcoming
+	since := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -filter.lookback)
+
+	for _, status := range statuses {
+		if !filter.match(status) {
+			continue
+		}
+
+		stamp := status.LastSuccess
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		for _, rel := range status.Releases {
+			for _, event := range releaseEvents(rel) {
+				if event.date.Before(since) {
+					continue
+				}
+				summary := fmt.Sprintf("%s %s %s", status.Name, rel.ReleaseCycleName, event.summary)
+				description := fmt.Sprintf("%s %s reaches %s on %s.\nLatest version: %s",
+					status.Name, rel.ReleaseCycleName, event.summary, event.date.Format(dateLayout), rel.LatestVersion)
+
+				writeCalendarLine(&b, "BEGIN:VEVENT")
+				writeCalendarLine(&b, fmt.Sprintf("UID:%s-%s-%s@endoflife_exporter", status.Name, rel.ReleaseCycleName, event.kind))
+				writeCalendarLine(&b, "DTSTAMP:"+stamp.UTC().Format(calendarStampLayout))
+				writeCalendarLine(&b, "DTSTART;VALUE=DATE:"+event.date.Format(calendarDateLayout))
+				writeCalendarLine(&b, "DTEND;VALUE=DATE:"+event.date.AddDate(0, 0, 1).Format(calendarDateLayout))
+				writeCalendarLine(&b, "SUMMARY:"+escapeCalendarText(summary))
+				writeCalendarLine(&b, "DESCRIPTION:"+escapeCalendarText(description))
+				writeCalendarLine(&b, "CATEGORIES:"+strings.ToUpper(event.kind))
+				writeCalendarLine(&b, "TRANSP:TRANSPARENT")
+				if status.Link != "" {
+					writeCalendarLine(&b, "URL:"+status.Link)
+				}
+				for _, days := range filter.alarms {
+					writeCalendarLine(&b, "BEGIN:VALARM")
+					writeCalendarLine(&b, "ACTION:DISPLAY")
+					writeCalendarLine(&b, fmt.Sprintf("TRIGGER:-P%dD", days))
+					writeCalendarLine(&b, "DESCRIPTION:"+escapeCalendarText(fmt.Sprintf("%s in %d days", summary, days)))
+					writeCalendarLine(&b, "END:VALARM")
+				}
+				writeCalendarLine(&b, "END:VEVENT")
+			}
+		}
+	}
+
+	writeCalendarLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// writeCalendarLine writes a content line terminated by CRLF, folded at 75
+// octets as required by RFC 5545.
+func writeCalendarLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Don't split UTF-8 sequences
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space
+		limit = 74
+	}
+	b.WriteString(line + "\r\n")
+}
+
+func escapeCalendarText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
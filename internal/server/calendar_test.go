@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+var _ = Describe("Calendar", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(s string) time.Time {
+		t, _ := time.Parse(dateLayout, s)
+		return t
+	}
+
+	statuses := []collector.ProductStatus{
+		{
+			Name:        "mongo",
+			Link:        "https://endoflife.date/mongodb",
+			Labels:      map[string]string{"team": "data"},
+			LastSuccess: now,
+			Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "7.0", EOASFrom: day("2025-08-31"), EOLFrom: day("2027-08-31"), LatestVersion: "7.0.12"},
+			},
+		},
+		{
+			Name:        "rhel",
+			Labels:      map[string]string{"team": "platform"},
+			LastSuccess: now,
+			Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "9", EOLFrom: day("2032-05-31"), EOESFrom: day("2035-05-31"), LatestVersion: "9.5"},
+				{ReleaseCycleName: "10", EOLFrom: endoflife.UnknownDate, LatestVersion: "10.0"},
+			},
+		},
+	}
+
+	calendar := func(query string) string {
+		req := httptest.NewRequest("GET", "/calendar.ics?"+query, nil)
+		filter, err := parseCalendarFilter(req)
+		Expect(err).To(BeNil())
+		return newCalendar(statuses, filter, now)
+	}
+
+	It("should add an all-day event per upcoming EOL, EOAS and EOES date", func() {
+		ics := calendar("")
+
+		Expect(ics).To(HavePrefix("BEGIN:VCALENDAR\r\n"))
+		Expect(ics).To(HaveSuffix("END:VCALENDAR\r\n"))
+		Expect(strings.Count(ics, "BEGIN:VEVENT")).To(Equal(3))
+		Expect(ics).NotTo(ContainSubstring("UID:mongo-7.0-eoas@endoflife_exporter\r\n"))
+		Expect(ics).To(ContainSubstring("UID:mongo-7.0-eol@endoflife_exporter\r\nDTSTAMP:20260101T000000Z\r\nDTSTART;VALUE=DATE:20270831\r\nDTEND;VALUE=DATE:20270901\r\n"))
+		Expect(ics).To(ContainSubstring("UID:rhel-9-eoes@endoflife_exporter\r\n"))
+		Expect(ics).NotTo(ContainSubstring("rhel-10"))
+		Expect(ics).NotTo(ContainSubstring("VALARM"))
+	})
+
+	It("should add reminder alarms", func() {
+		ics := calendar("alarm=30,7")
+
+		Expect(strings.Count(ics, "BEGIN:VALARM")).To(Equal(6))
+		Expect(ics).To(ContainSubstring("TRIGGER:-P30D\r\n"))
+		Expect(ics).To(ContainSubstring("TRIGGER:-P7D\r\n"))
+	})
+
+	It("should filter by product and label", func() {
+		Expect(strings.Count(calendar("product=mongo"), "BEGIN:VEVENT")).To(Equal(1))
+		Expect(strings.Count(calendar("label.team=platform"), "BEGIN:VEVENT")).To(Equal(2))
+		Expect(strings.Count(calendar("label.team=platform,data"), "BEGIN:VEVENT")).To(Equal(3))
+		Expect(strings.Count(calendar("product=mongo&label.team=platform"), "BEGIN:VEVENT")).To(Equal(0))
+	})
+
+	It("should include past events within the lookback", func() {
+		Expect(calendar("lookback=90")).NotTo(ContainSubstring("UID:mongo-7.0-eoas@endoflife_exporter\r\n"))
+		ics := calendar("lookback=180")
+		Expect(strings.Count(ics, "BEGIN:VEVENT")).To(Equal(4))
+		Expect(ics).To(ContainSubstring("UID:mongo-7.0-eoas@endoflife_exporter\r\n"))
+	})
+
+	It("should reject invalid alarms and lookbacks", func() {
+		for _, query := range []string{"alarm=soon", "lookback=-1", "lookback=week"} {
+			req := httptest.NewRequest("GET", "/calendar.ics?"+query, nil)
+			_, err := parseCalendarFilter(req)
+			Expect(err).NotTo(BeNil(), query)
+		}
+	})
+
+	It("should escape and fold content lines", func() {
+		var b strings.Builder
+		writeCalendarLine(&b, "SUMMARY:"+escapeCalendarText(strings.Repeat("a,b;", 30)))
+
+		lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+		Expect(len(lines)).To(BeNumerically(">", 1))
+		for i, line := range lines {
+			Expect(len(line)).To(BeNumerically("<=", 75))
+			if i > 0 {
+				Expect(line).To(HavePrefix(" "))
+			}
+		}
+		Expect(b.String()).To(ContainSubstring(`a\,b\;`))
+	})
+})
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/changelog"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Updated  string       `xml:"updated"`
+	Category atomCategory `xml:"category"`
+	Link     *atomLink    `xml:"link,omitempty"`
+	Summary  string       `xml:"summary"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// FeedHandler serves the changes detected between refreshes as an Atom feed,
+// newest first.
+func FeedHandler(exporter *collector.Exporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selfURL := "http://" + r.Host + r.URL.Path
+		if r.TLS != nil {
+			selfURL = "https://" + r.Host + r.URL.Path
+		}
+
+		feed := newAtomFeed(exporter.Changes(), exporter.Products(), selfURL, time.Now())
+
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		if _, err := w.Write([]byte(xml.Header)); err != nil {
+			return
+		}
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		if err := enc.Encode(feed); err != nil {
+			slog.Warn("Failed to render feed", "error", err)
+		}
+	})
+}
+
+func newAtomFeed(changes []changelog.Change, statuses []collector.ProductStatus, selfURL string, now time.Time) atomFeed {
+	links := make(map[string]string, len(statuses))
+	for _, status := range statuses {
+		links[status.Name] = status.Link
+	}
+
+	feed := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      selfURL,
+		Title:   "End-of-Life Changes",
+		Updated: now.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "endoflife_exporter"},
+		Link:    atomLink{Href: selfURL, Rel: "self"},
+		Entries: []atomEntry{},
+	}
+	// Changes are newest first
+	if len(changes) > 0 {
+		feed.Updated = changes[0].Time.UTC().Format(time.RFC3339)
+	}
+
+	for _, c := range changes {
+		entry := atomEntry{
+			ID:       fmt.Sprintf("tag:endoflife_exporter,%s:%s/%s/%s/%s", c.Time.UTC().Format(dateLayout), c.Product, c.ReleaseCycle, c.Type, c.New),
+			Title:    c.Message(),
+			Updated:  c.Time.UTC().Format(time.RFC3339),
+			Category: atomCategory{Term: string(c.Type)},
+			Summary:  c.Message(),
+		}
+		if link := links[c.Product]; link != "" {
+			entry.Link = &atomLink{Href: link}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed
+}
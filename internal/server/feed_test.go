@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/xml"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/changelog"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+)
+
+var _ = Describe("Feed", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	It("should render an entry per change, newest first", func() {
+		changes := []changelog.Change{
+			{Time: now, Product: "mongo", ReleaseCycle: "7.0", Type: changelog.TypeNewLatestVersion, Old: "7.0.11", New: "7.0.12"},
+			{Time: now.Add(-time.Hour), Product: "redis", ReleaseCycle: "8.0", Type: changelog.TypeNewReleaseCycle, New: "8.0.0"},
+		}
+		statuses := []collector.ProductStatus{{Name: "mongo", Link: "https://endoflife.date/mongodb"}, {Name: "redis"}}
+
+		feed := newAtomFeed(changes, statuses, "http://localhost:8080/feed.atom", now.Add(time.Hour))
+
+		Expect(feed.Updated).To(Equal("2026-01-01T00:00:00Z"))
+		Expect(feed.Entries).To(HaveLen(2))
+		Expect(feed.Entries[0].Title).To(Equal("mongo 7.0: new latest version 7.0.12 (was 7.0.11)"))
+		Expect(feed.Entries[0].ID).To(Equal("tag:endoflife_exporter,2026-01-01:mongo/7.0/new_latest_version/7.0.12"))
+		Expect(feed.Entries[0].Link.Href).To(Equal("https://endoflife.date/mongodb"))
+		Expect(feed.Entries[1].Link).To(BeNil())
+
+		out, err := xml.Marshal(feed)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(HavePrefix(`<feed xmlns="http://www.w3.org/2005/Atom">`))
+		Expect(string(out)).To(ContainSubstring(`<category term="new_release_cycle"></category>`))
+	})
+
+	It("should render an empty feed without changes", func() {
+		feed := newAtomFeed(nil, nil, "http://localhost:8080/feed.atom", now)
+
+		Expect(feed.Updated).To(Equal("2026-01-01T00:00:00Z"))
+		Expect(feed.Entries).To(BeEmpty())
+	})
+})
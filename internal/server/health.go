@@ -0,0 +1,101 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+)
+
+type productHealth struct {
+	Name        string     `json:"name"`
+	Ready       bool       `json:"ready"`
+	Discovered  bool       `json:"discovered,omitempty"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+type readiness struct {
+	Status   string          `json:"status"`
+	MaxAge   string          `json:"max_age"`
+	Failing  []string        `json:"failing"`
+	Products []productHealth `json:"products"`
+}
+
+// HealthyHandler reports that the process is alive. It never depends on the
+// state of the cached data, so it is safe to use as a liveness probe.
+func HealthyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "healthy"})
+	})
+}
+
+// ReadyHandler returns 200 once every configured product has been fetched
+// successfully within maxAge, and 503 with the failing products otherwise.
+// Products that are only discovered are reported but never fail readiness,
+// e.g. a mapping to a product endoflife.date does not have.
+func ReadyHandler(exporter *collector.Exporter, maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := checkReadiness(exporter.Products(), maxAge, time.Now())
+
+		code := http.StatusOK
+		if resp.Status != "ready" {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, resp)
+	})
+}
+
+// checkReadiness evaluates the freshness of each product status at now.
+func checkReadiness(statuses []collector.ProductStatus, maxAge time.Duration, now time.Time) readiness {
+	resp := readiness{
+		Status:   "ready",
+		MaxAge:   maxAge.String(),
+		Failing:  []string{},
+		Products: make([]productHealth, 0, len(statuses)),
+	}
+
+	for _, status := range statuses {
+		ph := productHealth{Name: status.Name}
+		if !status.LastAttempt.IsZero() {
+			ph.LastAttempt = &status.LastAttempt
+		}
+		if !status.LastSuccess.IsZero() {
+			ph.LastSuccess = &status.LastSuccess
+		}
+
+		switch {
+		case status.LastSuccess.IsZero() && status.Err == nil:
+			ph.Error = "not fetched yet"
+		case status.LastSuccess.IsZero():
+			ph.Error = status.Err.Error()
+		case now.Sub(status.LastSuccess) > maxAge:
+			ph.Error = "cached data is older than " + maxAge.String()
+			if status.Err != nil {
+				ph.Error += ": " + status.Err.Error()
+			}
+		default:
+			ph.Ready = true
+		}
+
+		ph.Discovered = !status.Configured
+		if !ph.Ready && status.Configured {
+			resp.Status = "not ready"
+			resp.Failing = append(resp.Failing, status.Name)
+		}
+		resp.Products = append(resp.Products, ph)
+	}
+
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("Failed to write response", "error", err)
+	}
+}
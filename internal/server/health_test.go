@@ -0,0 +1,97 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+)
+
+func TestServer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Server Suite")
+}
+
+var _ = Describe("Server Suite", func() {
+	Context("When checking readiness", func() {
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		It("should be ready when all products were fetched recently", func() {
+			resp := checkReadiness([]collector.ProductStatus{
+				{Name: "mongo", Configured: true, LastAttempt: now.Add(-time.Hour), LastSuccess: now.Add(-time.Hour)},
+				{Name: "redis", Configured: true, LastAttempt: now.Add(-time.Hour), LastSuccess: now.Add(-time.Hour)},
+			}, 24*time.Hour, now)
+
+			Expect(resp.Status).To(Equal("ready"))
+			Expect(resp.Failing).To(BeEmpty())
+			Expect(resp.Products).To(HaveLen(2))
+		})
+
+		It("should not be ready before the first fetch", func() {
+			resp := checkReadiness([]collector.ProductStatus{
+				{Name: "mongo", Configured: true},
+			}, 24*time.Hour, now)
+
+			Expect(resp.Status).To(Equal("not ready"))
+			Expect(resp.Failing).To(ConsistOf("mongo"))
+			Expect(resp.Products[0].Error).To(Equal("not fetched yet"))
+		})
+
+		It("should report products that never fetched successfully", func() {
+			resp := checkReadiness([]collector.ProductStatus{
+				{Name: "mongo", Configured: true, LastAttempt: now, LastSuccess: now},
+				{Name: "redis", Configured: true, LastAttempt: now, Err: errors.New("API returned non-OK status: 404")},
+			}, 24*time.Hour, now)
+
+			Expect(resp.Status).To(Equal("not ready"))
+			Expect(resp.Failing).To(ConsistOf("redis"))
+			Expect(resp.Products[1].Error).To(ContainSubstring("404"))
+		})
+
+		It("should report discovered products without failing readiness", func() {
+			resp := checkReadiness([]collector.ProductStatus{
+				{Name: "mongo", Configured: true, LastAttempt: now, LastSuccess: now},
+				{Name: "postgres", LastAttempt: now, Err: errors.New("API returned non-OK status: 404")},
+			}, 24*time.Hour, now)
+
+			Expect(resp.Status).To(Equal("ready"))
+			Expect(resp.Failing).To(BeEmpty())
+			Expect(resp.Products[1].Ready).To(BeFalse())
+			Expect(resp.Products[1].Discovered).To(BeTrue())
+			Expect(resp.Products[1].Error).To(ContainSubstring("404"))
+		})
+
+		It("should not be ready when cached data is too old", func() {
+			resp := checkReadiness([]collector.ProductStatus{
+				{Name: "mongo", Configured: true, LastAttempt: now, LastSuccess: now.Add(-25 * time.Hour), Err: errors.New("timeout")},
+			}, 24*time.Hour, now)
+
+			Expect(resp.Status).To(Equal("not ready"))
+			Expect(resp.Failing).To(ConsistOf("mongo"))
+			Expect(resp.Products[0].Error).To(ContainSubstring("timeout"))
+		})
+
+		It("should stay ready while stale data is within max age", func() {
+			resp := checkReadiness([]collector.ProductStatus{
+				{Name: "mongo", Configured: true, LastAttempt: now, LastSuccess: now.Add(-2 * time.Hour), Err: errors.New("timeout")},
+			}, 24*time.Hour, now)
+
+			Expect(resp.Status).To(Equal("ready"))
+		})
+	})
+
+	Context("When probing liveness", func() {
+		It("should always return 200", func() {
+			rec := httptest.NewRecorder()
+			HealthyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/-/healthy", nil))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+		})
+	})
+})
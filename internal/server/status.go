@@ -0,0 +1,104 @@
+package server
+
+import (
+	"embed"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+)
+
+//go:embed templates/status.html
+var templatesFS embed.FS
+
+var statusTemplate = template.Must(template.ParseFS(templatesFS, "templates/status.html"))
+
+type statusRow struct {
+	Product           string
+	ProductLink       string
+	ReleaseCycle      string
+	IsLts             bool
+	Phase             lifecycle.Phase
+	EOLFrom           string
+	DaysToEOL         string
+	LatestVersion     string
+	LatestVersionLink string
+	Installed         []installedView
+
+	eolFrom time.Time
+}
+
+type statusError struct {
+	Product string
+	Error   string
+}
+
+type statusPage struct {
+	LastRefresh time.Time
+	Errors      []statusError
+	Rows        []statusRow
+}
+
+// StatusHandler renders an HTML page listing every tracked release cycle,
+// sorted by EOL date.
+func StatusHandler(exporter *collector.Exporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		page := newStatusPage(exporter.Products(), time.Now())
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := statusTemplate.Execute(w, page); err != nil {
+			slog.Warn("Failed to render status page", "error", err)
+		}
+	})
+}
+
+func newStatusPage(statuses []collector.ProductStatus, now time.Time) statusPage {
+	page := statusPage{}
+
+	for _, status := range statuses {
+		if status.LastSuccess.After(page.LastRefresh) {
+			page.LastRefresh = status.LastSuccess
+		}
+		if status.Err != nil {
+			page.Errors = append(page.Errors, statusError{Product: status.Name, Error: status.Err.Error()})
+		}
+
+		// Without a filter the views are in the same order as status.Releases
+		view := newProductView(status, releaseFilter{}, now)
+		for i, rv := range view.Releases {
+			row := statusRow{
+				Product:           status.Name,
+				ProductLink:       status.Link,
+				ReleaseCycle:      rv.Name,
+				IsLts:             rv.IsLts,
+				Phase:             lifecycle.Phase(rv.Phase),
+				EOLFrom:           rv.EOLFrom,
+				LatestVersion:     rv.LatestVersion,
+				LatestVersionLink: rv.LatestVersionLink,
+				Installed:         rv.Installed,
+				eolFrom:           status.Releases[i].EOLFrom,
+			}
+			if rv.DaysToEOL != nil {
+				row.DaysToEOL = strconv.Itoa(*rv.DaysToEOL)
+			}
+			page.Rows = append(page.Rows, row)
+		}
+	}
+
+	// Unknown EOL dates are far in the future, so they end up last
+	sort.SliceStable(page.Rows, func(i, j int) bool {
+		return page.Rows[i].eolFrom.Before(page.Rows[j].eolFrom)
+	})
+
+	return page
+}
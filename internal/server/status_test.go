@@ -0,0 +1,69 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/lifecycle"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+var _ = Describe("Status Page", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	statuses := []collector.ProductStatus{
+		{
+			Name:        "mongo",
+			Link:        "https://endoflife.date/mongodb",
+			LastSuccess: now.Add(-time.Hour),
+			Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "8.0", EOLFrom: now.AddDate(2, 0, 0), LatestVersion: "8.0.4", LatestVersionLink: "https://example.com/8.0.4"},
+				{ReleaseCycleName: "6.0", EOLFrom: now.AddDate(0, -1, 0), IsEol: true, LatestVersion: "6.0.20"},
+			},
+		},
+		{
+			Name:        "redis",
+			LastSuccess: now.Add(-2 * time.Hour),
+			Releases: []endoflife.ReleaseDetails{
+				{ReleaseCycleName: "7.2", EOLFrom: endoflife.UnknownDate, LatestVersion: "7.2.5"},
+				{ReleaseCycleName: "7.0", EOASFrom: now.AddDate(0, -2, 0), EOLFrom: now.AddDate(0, 3, 0), LatestVersion: "7.0.15"},
+			},
+		},
+	}
+
+	It("should sort releases by EOL date with unknown dates last", func() {
+		page := newStatusPage(statuses, now)
+
+		Expect(page.LastRefresh).To(Equal(now.Add(-time.Hour)))
+		Expect(page.Rows).To(HaveLen(4))
+		Expect(page.Rows[0].ReleaseCycle).To(Equal("6.0"))
+		Expect(page.Rows[0].Phase).To(Equal(lifecycle.PhaseEOL))
+		Expect(page.Rows[1].ReleaseCycle).To(Equal("7.0"))
+		Expect(page.Rows[1].Phase).To(Equal(lifecycle.PhaseSecurity))
+		Expect(page.Rows[2].ReleaseCycle).To(Equal("8.0"))
+		Expect(page.Rows[2].Phase).To(Equal(lifecycle.PhaseActive))
+		Expect(page.Rows[3].ReleaseCycle).To(Equal("7.2"))
+		Expect(page.Rows[3].EOLFrom).To(BeEmpty())
+	})
+
+	It("should render links and phases", func() {
+		rec := httptest.NewRecorder()
+		Expect(statusTemplate.Execute(rec, newStatusPage(statuses, now))).To(Succeed())
+
+		body := rec.Body.String()
+		Expect(body).To(ContainSubstring(`<a href="https://endoflife.date/mongodb">mongo</a>`))
+		Expect(body).To(ContainSubstring(`<a href="https://example.com/8.0.4">8.0.4</a>`))
+		Expect(body).To(ContainSubstring(`phase-security`))
+	})
+
+	It("should return 404 for unknown paths", func() {
+		rec := httptest.NewRecorder()
+		StatusHandler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+})
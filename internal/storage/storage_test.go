@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestStorage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Storage Suite")
+}
+
+var _ = Describe("Storage Suite", func() {
+	type doc struct {
+		Name string `json:"name"`
+	}
+
+	It("should save and load documents", func() {
+		dir := filepath.Join(GinkgoT().TempDir(), "data")
+		store, err := New(dir)
+		Expect(err).To(BeNil())
+
+		Expect(store.Save("doc.json", doc{Name: "mongo"})).To(Succeed())
+		Expect(store.Save("doc.json", doc{Name: "redis"})).To(Succeed())
+
+		loaded := doc{}
+		ok, err := store.Load("doc.json", &loaded)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(loaded.Name).To(Equal("redis"))
+
+		// No temporary files are left behind
+		entries, err := os.ReadDir(dir)
+		Expect(err).To(BeNil())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should report missing documents", func() {
+		store, err := New(GinkgoT().TempDir())
+		Expect(err).To(BeNil())
+
+		ok, err := store.Load("missing.json", &doc{})
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should fail on corrupt documents", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "doc.json"), []byte("{"), 0o644)).To(Succeed())
+		store, err := New(dir)
+		Expect(err).To(BeNil())
+
+		_, err = store.Load("doc.json", &doc{})
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -1,34 +1,37 @@
 package main
 
 import (
-	"context"
-	"errors"
-	"log"
+	"io"
 	"log/slog"
-	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/alecthomas/kong"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/veerendra2/endoflife_exporter/internal/collector"
-	"github.com/veerendra2/endoflife_exporter/internal/config"
 	"github.com/veerendra2/gopackages/slogger"
 	"github.com/veerendra2/gopackages/version"
 )
 
 const appName = "endoflife_exporter"
 
-var cli struct {
-	Address string           `env:"ADDRESS" default:":8080" help:"The address where the server should listen on."`
+// Globals are the flags shared by all commands.
+type Globals struct {
 	Config  string           `env:"CONFIG_FILE" default:"config.yml" help:"Configuration file path"`
 	Log     slogger.Config   `embed:"" prefix:"log." envprefix:"LOG_"`
 	Version kong.VersionFlag `name:"version" help:"Print version information and exit"`
 }
 
+var cli struct {
+	Globals
+
+	Serve             ServeCmd             `cmd:"" default:"withargs" help:"Run the exporter and serve metrics over HTTP (default)."`
+	Check             CheckCmd             `cmd:"" help:"Check tracked releases against EOL thresholds, for use as a CI gate."`
+	GenerateRules     GenerateRulesCmd     `cmd:"" help:"Generate Prometheus alerting rules from the alerting thresholds in the configuration."`
+	GenerateDashboard GenerateDashboardCmd `cmd:"" help:"Generate a Grafana dashboard for the exported metrics and custom labels."`
+	Push              PushCmd              `cmd:"" help:"Fetch all products once and push the metrics to a Prometheus Pushgateway."`
+	Textfile          TextfileCmd          `cmd:"" help:"Write the metrics to a file for the node_exporter textfile collector."`
+	Scan              ScanCmd              `cmd:"" help:"Scan repositories for pinned runtime versions and check them against EOL thresholds."`
+}
+
 func main() {
 	kongCtx := kong.Parse(&cli,
 		kong.Name(appName),
@@ -43,65 +46,31 @@ func main() {
 	)
 	kongCtx.FatalIfErrorf(kongCtx.Error)
 
-	slog.SetDefault(slogger.New(cli.Log))
-
-	slog.Info("Version information", version.Info()...)
-	slog.Info("Build context", version.BuildContext()...)
-
-	slog.Info("Loading configuration", "file", cli.Config)
-	cfg, err := config.LoadConfig(cli.Config)
-	if err != nil {
-		slog.Error("Failed to load configuration", "error", err)
-		os.Exit(1)
-	}
-
-	exporter, err := collector.NewExporter(*cfg)
-	if err != nil {
-		slog.Error("Failed to create exporter", "error", err)
-		os.Exit(1)
+	// Only the server logs to stdout, other commands write their results there
+	if kongCtx.Command() == "serve" {
+		slog.SetDefault(slogger.New(cli.Log))
+	} else {
+		slog.SetDefault(newLogger(cli.Log, os.Stderr))
 	}
 
-	prometheus.MustRegister(exporter)
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if _, err = w.Write([]byte("<body>Metrics are available at <a href=\"/metrics\">/metrics</a></body>")); err != nil {
-			slog.Warn("Failed to write", "error", err)
-		}
-	})
-	http.Handle("/metrics", promhttp.Handler())
+	kongCtx.FatalIfErrorf(kongCtx.Run(&cli.Globals))
+}
 
-	server := &http.Server{
-		Addr:              cli.Address,
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       10 * time.Second,
-		WriteTimeout:      10 * time.Second,
-		IdleTimeout:       30 * time.Second,
+// newLogger is like slogger.New, but writes to w.
+func newLogger(config slogger.Config, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		AddSource: config.AddSource,
+		Level:     config.Level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if t, ok := a.Value.Any().(time.Time); ok && a.Key == slog.TimeKey {
+				return slog.String(a.Key, t.Format(time.RFC3339))
+			}
+			return a
+		},
 	}
 
-	go func() {
-		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-			slog.Error("Server died unexpected.", "error", err)
-		}
-		slog.Error("Server stopped.")
-	}()
-
-	// All components should be terminated gracefully. For that we are listen
-	// for the SIGINT and SIGTERM signals and try to gracefully shutdown the
-	// started components. This ensures that established connections or tasks
-	// are not interrupted.
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
-
-	slog.Info("Listening", "address", cli.Address)
-	slog.Debug("Start listening for SIGINT and SIGTERM signal.")
-	<-done
-	slog.Info("Shutdown started.")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("HTTP shutdown error: %v", err)
+	if config.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
 	}
-
-	slog.Info("Shutdown done.")
+	return slog.New(slog.NewTextHandler(w, opts))
 }
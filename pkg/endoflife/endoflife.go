@@ -14,17 +14,33 @@ import (
 
 const EndOfLifeBaseURL = "https://endoflife.date/api/v1"
 
+// UnknownDate is used for EOLFrom and LatestVersionDate when the API does not
+// provide the date (2050-01-01).
+var UnknownDate = time.Unix(2524608000, 0)
+
 type ReleaseDetails struct {
+	EOASFrom          time.Time // Zero when the product has no active support phase
+	EOESFrom          time.Time // Zero when the product has no extended support phase
 	EOLFrom           time.Time
+	IsEoas            bool
 	IsEol             bool
 	IsLts             bool
 	IsMaintained      bool
 	LatestVersion     string
 	LatestVersionDate time.Time
+	LatestVersionLink string // Changelog or release notes, empty when unknown
 	ReleaseCycleDate  time.Time
 	ReleaseCycleName  string
 }
 
+// Product holds the details of a product and all of its release cycles.
+type Product struct {
+	Name     string
+	Label    string
+	Link     string // Product page on endoflife.date
+	Releases []ReleaseDetails
+}
+
 type client struct {
 	baseUrl    *url.URL
 	httpClient http.Client
@@ -32,8 +48,9 @@ type client struct {
 
 type Client interface {
 	doRequest(ctx context.Context, requestUrl string) ([]byte, error)
-	GetProductDetails(ctx context.Context, productName string) ([]ReleaseDetails, error)
+	GetProductDetails(ctx context.Context, productName string) (Product, error)
 	GetRelease(ctx context.Context, productName string, cycleName string) (ReleaseDetails, error)
+	GetIdentifiers(ctx context.Context, identifierType string) (map[string]string, error)
 }
 
 // doRequest does HTTP request to given requestUrl and returns response body
@@ -89,40 +106,74 @@ func (c *client) GetRelease(ctx context.Context, productName string, cycleName s
 	return releaseDetails, nil
 }
 
-// GetProductDetails retrieves all release cycles for a given product.
+// GetProductDetails retrieves the product details and all release cycles for a given product.
 // Endpoint: GET /products/{productName}
-func (c *client) GetProductDetails(ctx context.Context, productName string) ([]ReleaseDetails, error) {
+func (c *client) GetProductDetails(ctx context.Context, productName string) (Product, error) {
 	requestUrl := *c.baseUrl
-	releaseDetails := []ReleaseDetails{}
+	productDetails := Product{Name: productName, Releases: []ReleaseDetails{}}
 	product := ProductResponse{}
 
 	requestUrl.Path = path.Join(requestUrl.Path, "products", productName)
 
 	body, err := c.doRequest(ctx, requestUrl.String())
 	if err != nil {
-		return releaseDetails, err
+		return productDetails, err
 	}
 
 	if err := json.Unmarshal(body, &product); err != nil {
-		return releaseDetails, fmt.Errorf("failed to decode API response: %w", err)
+		return productDetails, fmt.Errorf("failed to decode API response: %w", err)
 	}
 
+	productDetails.Label = product.Result.Label
+	productDetails.Link = product.Result.Links.Html
 	for _, productRelease := range product.Result.Releases {
-		releaseDetails = append(releaseDetails, getReleaseDetails(productRelease))
+		productDetails.Releases = append(productDetails.Releases, getReleaseDetails(productRelease))
 	}
 
-	return releaseDetails, nil
+	return productDetails, nil
+}
+
+// GetIdentifiers retrieves all identifiers of a type, such as purl or cpe, and
+// returns the product name of each identifier.
+// Endpoint: GET /identifiers/{identifierType}
+func (c *client) GetIdentifiers(ctx context.Context, identifierType string) (map[string]string, error) {
+	requestUrl := *c.baseUrl
+	identifiers := IdentifierListResponse{}
+
+	requestUrl.Path = path.Join(requestUrl.Path, "identifiers", identifierType)
+
+	body, err := c.doRequest(ctx, requestUrl.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(body, &identifiers); err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	}
+
+	products := make(map[string]string, len(identifiers.Result))
+	for _, identifier := range identifiers.Result {
+		products[identifier.Identifier] = identifier.Product.Name
+	}
+
+	return products, nil
 }
 
 // getReleaseDetails converts a ProductRelease from the API response into a ReleaseDetails struct.
 func getReleaseDetails(productRelease ProductRelease) ReleaseDetails {
 	latestVersion := "N/A"
-	latestVersionDate := time.Unix(2524608000, 0) // Default is 2050-01-01 (same as EOL default)
-	eolFrom := time.Unix(2524608000, 0)           // Default is 2050-01-01
+	latestVersionDate := UnknownDate
+	latestVersionLink := ""
+	eolFrom := UnknownDate
+	eoasFrom := time.Time{}
+	eoesFrom := time.Time{}
 	releaseCycleDate := time.Unix(0, 0)
 
 	if productRelease.Latest != nil {
 		latestVersion = productRelease.Latest.Name
+		if productRelease.Latest.Link != nil {
+			latestVersionLink = *productRelease.Latest.Link
+		}
 		if productRelease.Latest.Date != nil {
 			if parsedDate, err := time.Parse("2006-01-02", productRelease.Latest.Date.String()); err == nil {
 				latestVersionDate = parsedDate
@@ -136,17 +187,33 @@ func getReleaseDetails(productRelease ProductRelease) ReleaseDetails {
 		}
 	}
 
+	if productRelease.EoasFrom != nil {
+		if parsedDate, err := time.Parse("2006-01-02", productRelease.EoasFrom.String()); err == nil {
+			eoasFrom = parsedDate
+		}
+	}
+
+	if productRelease.EoesFrom != nil {
+		if parsedDate, err := time.Parse("2006-01-02", productRelease.EoesFrom.String()); err == nil {
+			eoesFrom = parsedDate
+		}
+	}
+
 	if parsedDate, err := time.Parse("2006-01-02", productRelease.ReleaseDate.String()); err == nil {
 		releaseCycleDate = parsedDate
 	}
 
 	return ReleaseDetails{
+		EOASFrom:          eoasFrom,
+		EOESFrom:          eoesFrom,
 		EOLFrom:           eolFrom,
+		IsEoas:            productRelease.IsEoas != nil && *productRelease.IsEoas,
 		IsLts:             productRelease.IsLts,
 		IsEol:             productRelease.IsEol,
 		IsMaintained:      productRelease.IsMaintained,
 		LatestVersion:     latestVersion,
 		LatestVersionDate: latestVersionDate,
+		LatestVersionLink: latestVersionLink,
 		ReleaseCycleDate:  releaseCycleDate,
 		ReleaseCycleName:  productRelease.Name,
 	}
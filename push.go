@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+)
+
+type PushCmd struct {
+	URL         string            `name:"url" env:"PUSHGATEWAY_URL" required:"" help:"URL of the Pushgateway, e.g. http://pushgateway:9091."`
+	Job         string            `env:"PUSHGATEWAY_JOB" default:"endoflife_exporter" help:"Job label of the pushed metrics."`
+	Grouping    map[string]string `help:"Additional grouping key labels, e.g. instance=cluster-a."`
+	Username    string            `env:"PUSHGATEWAY_USERNAME" help:"Username for basic authentication."`
+	Password    string            `env:"PUSHGATEWAY_PASSWORD" help:"Password for basic authentication."`
+	Add         bool              `help:"Only replace metrics with the same names (HTTP POST) instead of the whole group (HTTP PUT)."`
+	Timeout     time.Duration     `default:"2m" help:"Timeout for fetching all products from the endoflife.date API."`
+	PushTimeout time.Duration     `default:"30s" help:"Timeout for pushing the metrics to the Pushgateway."`
+}
+
+// fetchError is returned by commands that still deliver their metrics when
+// some products failed to fetch.
+type fetchError struct {
+	err error
+}
+
+func (e fetchError) Error() string {
+	return fmt.Sprintf("failed to fetch some products: %s", e.err)
+}
+
+func (e fetchError) ExitCode() int {
+	return 2
+}
+
+// newExporter loads the configuration and creates the exporter.
+func newExporter(configFile string) (*collector.Exporter, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	exporter, err := collector.NewExporter(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+func (c *PushCmd) Run(globals *Globals) error {
+	exporter, err := newExporter(globals.Config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	// Failed products are pushed with endoflife_product_fetch_success 0
+	fetchErr := exporter.Refresh(ctx)
+	if fetchErr != nil {
+		slog.Warn("Failed to fetch some products", "error", fetchErr)
+	}
+
+	pusher := push.New(c.URL, c.Job).Collector(exporter)
+	for name, value := range c.Grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+	if c.Username != "" {
+		pusher = pusher.BasicAuth(c.Username, c.Password)
+	}
+
+	pushFn := pusher.PushContext
+	if c.Add {
+		pushFn = pusher.AddContext
+	}
+	pushCtx, pushCancel := context.WithTimeout(context.Background(), c.PushTimeout)
+	defer pushCancel()
+	if err := pushFn(pushCtx); err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	slog.Info("Pushed metrics", "url", c.URL, "job", c.Job)
+
+	if fetchErr != nil {
+		return fetchError{err: fetchErr}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/manifest"
+	"github.com/veerendra2/endoflife_exporter/internal/report"
+	"github.com/veerendra2/endoflife_exporter/pkg/endoflife"
+)
+
+type ScanCmd struct {
+	Path    string         `arg:"" type:"existingdir" default:"." help:"Directory to scan, a repository or a directory of repositories."`
+	Warn    model.Duration `default:"90d" help:"Warn about versions reaching EOL within this duration (exit code 1)."`
+	Fail    model.Duration `default:"30d" help:"Fail on versions reaching EOL within this duration or already EOL (exit code 2)."`
+	Format  string         `short:"o" enum:"table,json,markdown" default:"table" help:"Output format. Must be \"table\", \"json\" or \"markdown\"."`
+	Timeout time.Duration  `default:"5m" help:"Timeout for scanning and fetching all products from the endoflife.date API."`
+}
+
+func (c *ScanCmd) Validate() error {
+	if c.Fail > c.Warn {
+		return fmt.Errorf("--fail (%s) must not be greater than --warn (%s)", c.Fail, c.Warn)
+	}
+	return nil
+}
+
+func (c *ScanCmd) Run(globals *Globals) error {
+	// The configuration is optional, only its discovery mappings are used
+	cfg := &config.Config{}
+	if _, err := os.Stat(globals.Config); err == nil {
+		if cfg, err = config.LoadConfig(globals.Config); err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	exporter, err := collector.NewExporter(config.Config{Discovery: cfg.Discovery})
+	if err != nil {
+		return fmt.Errorf("failed to create exporter: %w", err)
+	}
+
+	ec, err := endoflife.NewClient()
+	if err != nil {
+		return err
+	}
+	source := manifest.New(c.Path, discovery.NewResolver(cfg.Discovery.Mappings, ec))
+	if err := exporter.SetDiscovery(discovery.New(source)); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	if err := exporter.Refresh(ctx); err != nil {
+		slog.Warn("Failed to scan or fetch some products", "error", err)
+	}
+
+	rep := report.EvaluateScan(exporter.Products(), report.Thresholds{
+		Warn: time.Duration(c.Warn),
+		Fail: time.Duration(c.Fail),
+	}, time.Now())
+
+	if err := rep.Write(os.Stdout, c.Format); err != nil {
+		return err
+	}
+
+	if status := rep.Status(); status != report.StatusOK {
+		return checkError{status: status, count: rep.Count(status)}
+	}
+	return nil
+}
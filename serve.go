@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/veerendra2/endoflife_exporter/internal/collector"
+	"github.com/veerendra2/endoflife_exporter/internal/config"
+	"github.com/veerendra2/endoflife_exporter/internal/discovery/sbom"
+	"github.com/veerendra2/endoflife_exporter/internal/history"
+	"github.com/veerendra2/endoflife_exporter/internal/notify"
+	"github.com/veerendra2/endoflife_exporter/internal/otlp"
+	"github.com/veerendra2/endoflife_exporter/internal/remotewrite"
+	"github.com/veerendra2/endoflife_exporter/internal/server"
+	"github.com/veerendra2/endoflife_exporter/internal/storage"
+	"github.com/veerendra2/gopackages/version"
+)
+
+type ServeCmd struct {
+	Address         string        `env:"ADDRESS" default:":8080" help:"The address where the server should listen on."`
+	RefreshInterval time.Duration `env:"REFRESH_INTERVAL" default:"6h" help:"How often product data is fetched from the endoflife.date API."`
+	MaxDataAge      time.Duration `env:"MAX_DATA_AGE" default:"24h" help:"Maximum age of the cached product data before the exporter reports not ready."`
+
+	Storage     storage.Config     `embed:"" prefix:"storage." envprefix:"STORAGE_"`
+	OTLP        otlp.Config        `embed:"" prefix:"otlp." envprefix:"OTLP_"`
+	RemoteWrite remotewrite.Config `embed:"" prefix:"remote-write." envprefix:"REMOTE_WRITE_"`
+	Discovery   DiscoveryFlags     `embed:"" prefix:"discovery." envprefix:"DISCOVERY_"`
+}
+
+const snapshotFile = "snapshot.json"
+
+func (c *ServeCmd) Run(globals *Globals) error {
+	slog.Info("Version information", version.Info()...)
+	slog.Info("Build context", version.BuildContext()...)
+
+	slog.Info("Loading configuration", "file", globals.Config)
+	cfg, err := config.LoadConfig(globals.Config)
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	exporter, err := collector.NewExporter(*cfg)
+	if err != nil {
+		slog.Error("Failed to create exporter", "error", err)
+		os.Exit(1)
+	}
+
+	discoverer, err := newDiscoverer(c.Discovery, cfg)
+	if err != nil {
+		slog.Error("Failed to create discovery", "error", err)
+		os.Exit(1)
+	}
+	if discoverer != nil {
+		if err := exporter.SetDiscovery(discoverer); err != nil {
+			slog.Error("Failed to enable discovery", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Discovering installed versions", "sources", len(discoverer.Sources()))
+	}
+
+	// Warm start from the last persisted snapshot, saved again after every refresh
+	if c.Storage.Path != "" {
+		store, err := storage.New(c.Storage.Path)
+		if err != nil {
+			slog.Error("Failed to open storage", "error", err)
+			os.Exit(1)
+		}
+
+		snapshot := collector.Snapshot{}
+		if ok, err := store.Load(snapshotFile, &snapshot); err != nil {
+			slog.Warn("Failed to load snapshot, starting without cached data", "error", err)
+		} else if ok {
+			exporter.Restore(snapshot)
+			slog.Info("Loaded snapshot", "path", c.Storage.Path, "products", len(snapshot.Products))
+		}
+
+		dateHistory, err := history.Load(store)
+		if err != nil {
+			slog.Error("Failed to load date history", "error", err)
+			os.Exit(1)
+		}
+		exporter.SetHistory(dateHistory)
+
+		exporter.OnRefresh(func() {
+			if err := store.Save(snapshotFile, exporter.Snapshot()); err != nil {
+				slog.Warn("Failed to save snapshot", "error", err)
+			}
+		})
+	}
+
+	// Product data is fetched in the background, scrapes are served from the cache
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+
+	if len(cfg.Notifications.Webhooks) > 0 {
+		notifier, err := notify.New(*cfg)
+		if err != nil {
+			slog.Error("Failed to create notifier", "error", err)
+			os.Exit(1)
+		}
+		exporter.OnRefresh(notifier.Trigger)
+		go notifier.Run(refreshCtx, exporter.Products)
+		slog.Info("Sending notifications", "webhooks", len(cfg.Notifications.Webhooks), "state_file", cfg.Notifications.StateFile)
+	}
+
+	go exporter.Run(refreshCtx, c.RefreshInterval)
+
+	// New SBOMs are discovered ahead of the refresh interval
+	if c.Discovery.SBOM.Path != "" && c.Discovery.SBOM.Interval > 0 {
+		go sbom.Watch(refreshCtx, c.Discovery.SBOM.Path, c.Discovery.SBOM.Interval, exporter.RefreshNow)
+	}
+
+	prometheus.MustRegister(exporter)
+
+	// Only the exporter's metrics are pushed, not the Go runtime metrics
+	pushRegistry := prometheus.NewRegistry()
+	pushRegistry.MustRegister(exporter)
+
+	shutdownOTLP := func(context.Context) error { return nil }
+	if c.OTLP.Endpoint != "" {
+		shutdownOTLP, err = otlp.Start(refreshCtx, c.OTLP, pushRegistry)
+		if err != nil {
+			slog.Error("Failed to start OTLP export", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Exporting metrics over OTLP", "endpoint", c.OTLP.Endpoint, "protocol", c.OTLP.Protocol, "interval", c.OTLP.Interval)
+	}
+
+	if c.RemoteWrite.URL != "" {
+		go remotewrite.New(c.RemoteWrite, pushRegistry).Run(refreshCtx)
+		slog.Info("Sending metrics over remote write", "url", c.RemoteWrite.URL, "interval", c.RemoteWrite.Interval)
+	}
+
+	http.Handle("/", server.StatusHandler(exporter))
+	http.Handle("/metrics", promhttp.Handler())
+	http.Handle("/-/healthy", server.HealthyHandler())
+	http.Handle("/-/ready", server.ReadyHandler(exporter, c.MaxDataAge))
+	http.Handle("/api/v1/", server.APIHandler(exporter))
+	http.Handle("/calendar.ics", server.CalendarHandler(exporter))
+	http.Handle("/feed.atom", server.FeedHandler(exporter))
+
+	httpServer := &http.Server{
+		Addr:              c.Address,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       30 * time.Second,
+	}
+
+	go func() {
+		if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Server died unexpected.", "error", err)
+		}
+		slog.Error("Server stopped.")
+	}()
+
+	// All components should be terminated gracefully. For that we are listen
+	// for the SIGINT and SIGTERM signals and try to gracefully shutdown the
+	// started components. This ensures that established connections or tasks
+	// are not interrupted.
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
+
+	slog.Info("Listening", "address", c.Address)
+	slog.Debug("Start listening for SIGINT and SIGTERM signal.")
+	<-done
+	slog.Info("Shutdown started.")
+	stopRefresh()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := shutdownOTLP(ctx); err != nil {
+		slog.Warn("OTLP shutdown error", "error", err)
+	}
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Fatalf("HTTP shutdown error: %v", err)
+	}
+
+	slog.Info("Shutdown done.")
+	return nil
+}
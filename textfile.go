@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type TextfileCmd struct {
+	Path     string        `env:"TEXTFILE_PATH" required:"" help:"File the metrics are written to, e.g. /var/lib/node_exporter/textfile_collector/endoflife.prom."`
+	Interval time.Duration `env:"TEXTFILE_INTERVAL" help:"Write the metrics every interval until stopped. Written once if not set."`
+	Timeout  time.Duration `default:"2m" help:"Timeout for fetching all products from the endoflife.date API (once only)."`
+}
+
+func (c *TextfileCmd) Validate() error {
+	if !strings.HasSuffix(c.Path, ".prom") {
+		return fmt.Errorf("--path %q must end with .prom to be read by the node_exporter textfile collector", c.Path)
+	}
+	return nil
+}
+
+func (c *TextfileCmd) Run(globals *Globals) error {
+	exporter, err := newExporter(globals.Config)
+	if err != nil {
+		return err
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(exporter)
+
+	// Written to a temporary file and renamed, node_exporter never reads a partial file
+	write := func() error {
+		if err := prometheus.WriteToTextfile(c.Path, registry); err != nil {
+			return fmt.Errorf("failed to write metrics: %w", err)
+		}
+		slog.Info("Wrote metrics", "path", c.Path)
+		return nil
+	}
+
+	if c.Interval == 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+		defer cancel()
+
+		fetchErr := exporter.Refresh(ctx)
+		if fetchErr != nil {
+			slog.Warn("Failed to fetch some products", "error", fetchErr)
+		}
+		if err := write(); err != nil {
+			return err
+		}
+		if fetchErr != nil {
+			return fetchError{err: fetchErr}
+		}
+		return nil
+	}
+
+	exporter.OnRefresh(func() {
+		if err := write(); err != nil {
+			slog.Error("Failed to write textfile", "error", err)
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("Writing metrics periodically", "path", c.Path, "interval", c.Interval)
+	exporter.Run(ctx, c.Interval)
+	return nil
+}